@@ -0,0 +1,456 @@
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Structured AuxiliaryData starts with auxDataMagic and auxDataVersion, followed
+// by records sorted by strictly increasing tag, and ends with auxDataEnd:
+//
+//	tag (1 byte) | len(value) (uvarint) | value
+//
+// The end marker keeps the records intact when trailing zeros are trimmed or
+// appended, which TrackingID.Equals and CanonicalBytes consider insignificant.
+//
+// AuxiliaryData that is not framed this way is an opaque payload, as produced before
+// structured records existed, even if it starts with the magic. When the only record
+// is the payload, it is stored as-is unless it would be read back as records, so
+// short payloads keep fitting the 32 bytes of the version 1 TrackingID string format.
+const (
+	auxDataMagic   byte = 0xa7
+	auxDataVersion byte = 1
+	auxDataEnd     byte = 0xff
+)
+
+// AuxTag identifies a record of structured AuxiliaryData.
+type AuxTag byte
+
+const (
+	// AuxTagPayload holds opaque, caller-defined bytes.
+	AuxTagPayload AuxTag = 1
+	// AuxTagAttempt holds the retry attempt number.
+	AuxTagAttempt AuxTag = 2
+	// AuxTagSessionPath identifies a sub-session derived from a root request.
+	AuxTagSessionPath AuxTag = 3
+	// AuxTagKeyID identifies the key a session operates on.
+	AuxTagKeyID AuxTag = 4
+	// AuxTagChainID identifies the chain a signature is intended for.
+	AuxTagChainID AuxTag = 5
+	// AuxTagEpoch holds the committee epoch.
+	AuxTagEpoch AuxTag = 6
+	// AuxTagExpiry holds the time after which the session must be abandoned, in Unix seconds.
+	AuxTagExpiry AuxTag = 7
+
+	// auxTagMinCustom is the first tag available to RegisterAuxTag.
+	auxTagMinCustom AuxTag = 0x80
+)
+
+// AuxValueKind describes how the value of an AuxTag is encoded.
+type AuxValueKind int
+
+const (
+	// AuxValueBytes values are arbitrary bytes.
+	AuxValueBytes AuxValueKind = iota
+	// AuxValueUint values are uvarint encoded unsigned integers.
+	AuxValueUint
+)
+
+// AuxTagInfo describes a registered AuxTag.
+type AuxTagInfo struct {
+	Tag  AuxTag
+	Name string
+	Kind AuxValueKind
+	// Size is the exact size of bytes values, 0 if variable.
+	Size int
+}
+
+var (
+	errAuxDataTagOrder = fmt.Errorf("AuxiliaryData records must have strictly increasing tags")
+	errAuxDataUnknown  = fmt.Errorf("unknown AuxiliaryData tag")
+	errAuxDataValue    = fmt.Errorf("invalid AuxiliaryData value")
+	errAuxDataKind     = fmt.Errorf("AuxiliaryData tag has a different value kind")
+	errAuxTagReserved  = fmt.Errorf("AuxiliaryData tag is reserved")
+	errAuxTagTaken     = fmt.Errorf("AuxiliaryData tag already registered")
+)
+
+var auxTags = struct {
+	mtx  sync.RWMutex
+	byID map[AuxTag]AuxTagInfo
+}{
+	byID: map[AuxTag]AuxTagInfo{
+		AuxTagPayload:     {Tag: AuxTagPayload, Name: "payload", Kind: AuxValueBytes},
+		AuxTagAttempt:     {Tag: AuxTagAttempt, Name: "attempt", Kind: AuxValueUint},
+		AuxTagSessionPath: {Tag: AuxTagSessionPath, Name: "session_path", Kind: AuxValueBytes, Size: sessionPathSize},
+		AuxTagKeyID:       {Tag: AuxTagKeyID, Name: "key_id", Kind: AuxValueBytes},
+		AuxTagChainID:     {Tag: AuxTagChainID, Name: "chain_id", Kind: AuxValueUint},
+		AuxTagEpoch:       {Tag: AuxTagEpoch, Name: "epoch", Kind: AuxValueUint},
+		AuxTagExpiry:      {Tag: AuxTagExpiry, Name: "expiry", Kind: AuxValueUint},
+	},
+}
+
+// RegisterAuxTag adds an application specific tag. Tags below 0x80 are reserved for tss-common.
+func RegisterAuxTag(info AuxTagInfo) error {
+	if info.Tag < auxTagMinCustom || info.Tag == AuxTag(auxDataEnd) {
+		return fmt.Errorf("%w: %d", errAuxTagReserved, info.Tag)
+	}
+
+	auxTags.mtx.Lock()
+	defer auxTags.mtx.Unlock()
+
+	if existing, ok := auxTags.byID[info.Tag]; ok {
+		return fmt.Errorf("%w: %d (%s)", errAuxTagTaken, existing.Tag, existing.Name)
+	}
+
+	auxTags.byID[info.Tag] = info
+
+	return nil
+}
+
+// LookupAuxTag returns the registered AuxTagInfo of the tag.
+func LookupAuxTag(tag AuxTag) (AuxTagInfo, bool) {
+	auxTags.mtx.RLock()
+	defer auxTags.mtx.RUnlock()
+
+	info, ok := auxTags.byID[tag]
+	return info, ok
+}
+
+func validateAuxValue(tag AuxTag, value []byte) error {
+	info, ok := LookupAuxTag(tag)
+	if !ok {
+		return fmt.Errorf("%w: %d", errAuxDataUnknown, tag)
+	}
+
+	switch {
+	case info.Kind == AuxValueUint:
+		if n, read := binary.Uvarint(value); read <= 0 || read != len(value) || len(binary.AppendUvarint(nil, n)) != len(value) {
+			return fmt.Errorf("%w: %s is not a canonical uvarint", errAuxDataValue, info.Name)
+		}
+	case info.Size > 0 && len(value) != info.Size:
+		return fmt.Errorf("%w: %s must be %d bytes", errAuxDataValue, info.Name, info.Size)
+	}
+
+	return nil
+}
+
+// AuxiliaryData is the decoded form of TrackingID.AuxiliaryData.
+type AuxiliaryData struct {
+	records map[AuxTag][]byte
+}
+
+// NewAuxiliaryData returns an empty AuxiliaryData.
+func NewAuxiliaryData() *AuxiliaryData {
+	return &AuxiliaryData{records: map[AuxTag][]byte{}}
+}
+
+// auxRecord is a record of structured AuxiliaryData, in encoding order.
+type auxRecord struct {
+	tag   AuxTag
+	value []byte
+}
+
+// splitAuxRecords parses the framing of structured AuxiliaryData: the magic and version,
+// well-formed records, the end marker and zero padding. It reports false for anything
+// else, which is an opaque payload.
+func splitAuxRecords(b []byte) ([]auxRecord, bool) {
+	if len(b) < 3 || b[0] != auxDataMagic || b[1] != auxDataVersion {
+		return nil, false
+	}
+
+	var records []auxRecord
+	r := bytes.NewReader(b[2:])
+	for {
+		tagByte, err := r.ReadByte()
+		if err != nil {
+			return nil, false
+		}
+
+		if tagByte == auxDataEnd {
+			break
+		}
+
+		l, err := binary.ReadUvarint(r)
+		if err != nil || l > uint64(r.Len()) {
+			return nil, false
+		}
+
+		value := make([]byte, l)
+		_, _ = r.Read(value)
+		records = append(records, auxRecord{tag: AuxTag(tagByte), value: value})
+	}
+
+	for r.Len() > 0 {
+		if v, _ := r.ReadByte(); v != 0 {
+			return nil, false
+		}
+	}
+
+	return records, true
+}
+
+func isStructuredAuxData(b []byte) bool {
+	_, ok := splitAuxRecords(b)
+	return ok
+}
+
+// DecodeAuxiliaryData parses the AuxiliaryData of a TrackingID. Data that is not framed as
+// structured records, including legacy data that merely starts with the magic byte, is
+// returned as an AuxTagPayload record. Unknown tags, duplicated tags and malformed values
+// of structured data are rejected.
+func DecodeAuxiliaryData(b []byte) (*AuxiliaryData, error) {
+	aux := NewAuxiliaryData()
+	if len(b) == 0 {
+		return aux, nil
+	}
+
+	records, ok := splitAuxRecords(b)
+	if !ok {
+		aux.records[AuxTagPayload] = append([]byte{}, b...)
+		return aux, nil
+	}
+
+	prev := -1
+	for _, rec := range records {
+		if int(rec.tag) <= prev {
+			return nil, fmt.Errorf("%w: tag %d after %d", errAuxDataTagOrder, rec.tag, prev)
+		}
+
+		prev = int(rec.tag)
+
+		if err := validateAuxValue(rec.tag, rec.value); err != nil {
+			return nil, err
+		}
+
+		aux.records[rec.tag] = rec.value
+	}
+
+	return aux, nil
+}
+
+// Encode returns the bytes to store in TrackingID.AuxiliaryData, nil if there are no records.
+func (a *AuxiliaryData) Encode() []byte {
+	if len(a.records) == 0 {
+		return nil
+	}
+
+	if payload, ok := a.records[AuxTagPayload]; ok && len(a.records) == 1 && !isStructuredAuxData(payload) {
+		return append([]byte{}, payload...)
+	}
+
+	buf := []byte{auxDataMagic, auxDataVersion}
+	for _, tag := range a.Tags() {
+		buf = append(buf, byte(tag))
+		buf = binary.AppendUvarint(buf, uint64(len(a.records[tag])))
+		buf = append(buf, a.records[tag]...)
+	}
+
+	return append(buf, auxDataEnd)
+}
+
+// Tags returns the tags of the records in increasing order.
+func (a *AuxiliaryData) Tags() []AuxTag {
+	tags := make([]AuxTag, 0, len(a.records))
+	for tag := range a.records {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	return tags
+}
+
+// Has reports whether a record with the tag is present.
+func (a *AuxiliaryData) Has(tag AuxTag) bool {
+	_, ok := a.records[tag]
+	return ok
+}
+
+// Delete removes the record with the tag, if any.
+func (a *AuxiliaryData) Delete(tag AuxTag) {
+	delete(a.records, tag)
+}
+
+// Bytes returns a copy of the value of the record with the tag.
+func (a *AuxiliaryData) Bytes(tag AuxTag) ([]byte, bool) {
+	v, ok := a.records[tag]
+	if !ok {
+		return nil, false
+	}
+
+	return append([]byte{}, v...), true
+}
+
+// SetBytes sets the value of a registered bytes tag.
+func (a *AuxiliaryData) SetBytes(tag AuxTag, value []byte) error {
+	if info, ok := LookupAuxTag(tag); ok && info.Kind != AuxValueBytes {
+		return fmt.Errorf("%w: %s", errAuxDataKind, info.Name)
+	}
+
+	if err := validateAuxValue(tag, value); err != nil {
+		return err
+	}
+
+	a.records[tag] = append([]byte{}, value...)
+
+	return nil
+}
+
+// Uint64 returns the value of the record with the tag, which must be a registered uint tag.
+func (a *AuxiliaryData) Uint64(tag AuxTag) (uint64, bool, error) {
+	info, ok := LookupAuxTag(tag)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: %d", errAuxDataUnknown, tag)
+	}
+
+	if info.Kind != AuxValueUint {
+		return 0, false, fmt.Errorf("%w: %s", errAuxDataKind, info.Name)
+	}
+
+	v, ok := a.records[tag]
+	if !ok {
+		return 0, false, nil
+	}
+
+	n, _ := binary.Uvarint(v)
+
+	return n, true, nil
+}
+
+// SetUint64 sets the value of a registered uint tag.
+func (a *AuxiliaryData) SetUint64(tag AuxTag, n uint64) error {
+	info, ok := LookupAuxTag(tag)
+	if !ok {
+		return fmt.Errorf("%w: %d", errAuxDataUnknown, tag)
+	}
+
+	if info.Kind != AuxValueUint {
+		return fmt.Errorf("%w: %s", errAuxDataKind, info.Name)
+	}
+
+	a.records[tag] = binary.AppendUvarint(nil, n)
+
+	return nil
+}
+
+// ----- TrackingID accessors ----- //
+
+// AuxData decodes the AuxiliaryData of the TrackingID, see DecodeAuxiliaryData.
+func (t *TrackingID) AuxData() (*AuxiliaryData, error) {
+	if t == nil {
+		return nil, errNilTrackID
+	}
+
+	return DecodeAuxiliaryData(t.AuxiliaryData)
+}
+
+// SetAuxData replaces the AuxiliaryData of the TrackingID with the encoding of aux.
+func (t *TrackingID) SetAuxData(aux *AuxiliaryData) {
+	t.AuxiliaryData = aux.Encode()
+}
+
+func (t *TrackingID) auxUint64(tag AuxTag) (uint64, bool, error) {
+	aux, err := t.AuxData()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return aux.Uint64(tag)
+}
+
+func (t *TrackingID) setAuxUint64(tag AuxTag, n uint64) error {
+	aux, err := t.AuxData()
+	if err != nil {
+		return err
+	}
+
+	if err := aux.SetUint64(tag, n); err != nil {
+		return err
+	}
+
+	t.SetAuxData(aux)
+
+	return nil
+}
+
+// KeyID returns the key identifier stored in the AuxiliaryData, if any.
+func (t *TrackingID) KeyID() ([]byte, bool, error) {
+	aux, err := t.AuxData()
+	if err != nil {
+		return nil, false, err
+	}
+
+	keyID, ok := aux.Bytes(AuxTagKeyID)
+
+	return keyID, ok, nil
+}
+
+// SetKeyID stores the key identifier in the AuxiliaryData.
+func (t *TrackingID) SetKeyID(keyID []byte) error {
+	aux, err := t.AuxData()
+	if err != nil {
+		return err
+	}
+
+	if err := aux.SetBytes(AuxTagKeyID, keyID); err != nil {
+		return err
+	}
+
+	t.SetAuxData(aux)
+
+	return nil
+}
+
+// ChainID returns the chain identifier stored in the AuxiliaryData, if any.
+func (t *TrackingID) ChainID() (uint64, bool, error) {
+	return t.auxUint64(AuxTagChainID)
+}
+
+// SetChainID stores the chain identifier in the AuxiliaryData.
+func (t *TrackingID) SetChainID(chainID uint64) error {
+	return t.setAuxUint64(AuxTagChainID, chainID)
+}
+
+// Epoch returns the committee epoch stored in the AuxiliaryData, if any.
+func (t *TrackingID) Epoch() (uint64, bool, error) {
+	return t.auxUint64(AuxTagEpoch)
+}
+
+// SetEpoch stores the committee epoch in the AuxiliaryData.
+func (t *TrackingID) SetEpoch(epoch uint64) error {
+	return t.setAuxUint64(AuxTagEpoch, epoch)
+}
+
+// Expiry returns the expiry time stored in the AuxiliaryData, if any.
+func (t *TrackingID) Expiry() (time.Time, bool, error) {
+	secs, ok, err := t.auxUint64(AuxTagExpiry)
+	if err != nil || !ok {
+		return time.Time{}, ok, err
+	}
+
+	return time.Unix(int64(secs), 0), true, nil
+}
+
+// SetExpiry stores the expiry time, truncated to the second, in the AuxiliaryData.
+func (t *TrackingID) SetExpiry(expiry time.Time) error {
+	if expiry.Unix() < 0 {
+		return fmt.Errorf("%w: expiry before the Unix epoch", errAuxDataValue)
+	}
+
+	return t.setAuxUint64(AuxTagExpiry, uint64(expiry.Unix()))
+}
+
+// IsExpired reports whether the expiry stored in the AuxiliaryData is before now.
+// TrackingIDs without expiry never expire.
+func (t *TrackingID) IsExpired(now time.Time) (bool, error) {
+	expiry, ok, err := t.Expiry()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return now.After(expiry), nil
+}
@@ -0,0 +1,184 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuxData_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+		// opaque inputs are not framed as records, and decode as a payload.
+		opaque bool
+	}{
+		{"version", []byte{auxDataMagic, 9, auxDataEnd}, nil, true},
+		{"no end", []byte{auxDataMagic, auxDataVersion, byte(AuxTagAttempt), 1, 1}, nil, true},
+		{"truncated", []byte{auxDataMagic, auxDataVersion, byte(AuxTagAttempt), 5, 1}, nil, true},
+		{"trailing data", []byte{auxDataMagic, auxDataVersion, auxDataEnd, 0, 1}, nil, true},
+		{"magic only", []byte{auxDataMagic}, nil, true},
+		{"unknown tag", []byte{auxDataMagic, auxDataVersion, 0x7f, 0, auxDataEnd}, errAuxDataUnknown, false},
+		{"duplicate tag", []byte{auxDataMagic, auxDataVersion, byte(AuxTagAttempt), 1, 1, byte(AuxTagAttempt), 1, 2, auxDataEnd}, errAuxDataTagOrder, false},
+		{"zero padding", []byte{auxDataMagic, auxDataVersion, auxDataEnd, 0, 0}, nil, false},
+		{"non-canonical uint", []byte{auxDataMagic, auxDataVersion, byte(AuxTagEpoch), 2, 0x81, 0x00, auxDataEnd}, errAuxDataValue, false},
+		{"session path size", []byte{auxDataMagic, auxDataVersion, byte(AuxTagSessionPath), 1, 1, auxDataEnd}, errAuxDataValue, false},
+	}
+
+	for _, tt := range tests {
+		aux, err := DecodeAuxiliaryData(tt.input)
+		if tt.err == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: DecodeAuxiliaryData() = %v, want %v", tt.name, err, tt.err)
+			}
+			continue
+		}
+
+		if payload, _ := aux.Bytes(AuxTagPayload); tt.opaque && !bytes.Equal(payload, tt.input) {
+			t.Errorf("%s: payload = %x, want %x", tt.name, payload, tt.input)
+		}
+
+		if tt.opaque && !bytes.Equal(aux.Encode(), tt.input) {
+			t.Errorf("%s: opaque data must be encoded as-is, got %x", tt.name, aux.Encode())
+		}
+	}
+}
+
+func TestTrackingID_AuxDataAccessors(t *testing.T) {
+	tid := &TrackingID{Protocol: 1, Digest: []byte{1}, AuxiliaryData: []byte("legacy")}
+
+	expiry := time.Unix(1700000000, 0)
+	if err := tid.SetKeyID([]byte("key-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tid.SetChainID(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tid.SetEpoch(42); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tid.SetExpiry(expiry); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed TrackingID
+	if err := parsed.FromString(tid.ToString()); err != nil {
+		t.Fatal(err)
+	}
+
+	if keyID, ok, err := parsed.KeyID(); err != nil || !ok || string(keyID) != "key-1" {
+		t.Fatalf("KeyID() = %q, %v, %v", keyID, ok, err)
+	}
+
+	if chainID, ok, err := parsed.ChainID(); err != nil || !ok || chainID != 2 {
+		t.Fatalf("ChainID() = %d, %v, %v", chainID, ok, err)
+	}
+
+	if epoch, ok, err := parsed.Epoch(); err != nil || !ok || epoch != 42 {
+		t.Fatalf("Epoch() = %d, %v, %v", epoch, ok, err)
+	}
+
+	if got, ok, err := parsed.Expiry(); err != nil || !ok || !got.Equal(expiry) {
+		t.Fatalf("Expiry() = %v, %v, %v", got, ok, err)
+	}
+
+	if expired, _ := parsed.IsExpired(expiry.Add(time.Second)); !expired {
+		t.Fatalf("IsExpired() should be true after the expiry")
+	}
+
+	aux, err := parsed.AuxData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload, ok := aux.Bytes(AuxTagPayload); !ok || string(payload) != "legacy" {
+		t.Fatalf("the opaque payload should be preserved, got %q", payload)
+	}
+
+	if _, _, err := aux.Uint64(AuxTagKeyID); !errors.Is(err, errAuxDataKind) {
+		t.Fatalf("expected errAuxDataKind, got %v", err)
+	}
+
+	if err := aux.SetBytes(0x7e, []byte{1}); !errors.Is(err, errAuxDataUnknown) {
+		t.Fatalf("expected errAuxDataUnknown, got %v", err)
+	}
+}
+
+func TestAuxiliaryData_ShortPayloadCompatibility(t *testing.T) {
+	aux := NewAuxiliaryData()
+	if aux.Encode() != nil {
+		t.Fatalf("empty AuxiliaryData should encode to nil")
+	}
+
+	payload := bytes.Repeat([]byte{0x11}, 32)
+	if err := aux.SetBytes(AuxTagPayload, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(aux.Encode(), payload) {
+		t.Fatalf("a lone payload must be stored as-is")
+	}
+
+	// a payload that looks structured is wrapped to stay unambiguous.
+	ambiguous := []byte{auxDataMagic, auxDataVersion, auxDataEnd}
+	if err := aux.SetBytes(AuxTagPayload, ambiguous); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeAuxiliaryData(aux.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := decoded.Bytes(AuxTagPayload); !bytes.Equal(got, ambiguous) {
+		t.Fatalf("payload round-trip mismatch: %x", got)
+	}
+}
+
+// unregisterAuxTag removes a tag registered by a test.
+func unregisterAuxTag(tag AuxTag) {
+	auxTags.mtx.Lock()
+	defer auxTags.mtx.Unlock()
+
+	delete(auxTags.byID, tag)
+}
+
+func TestRegisterAuxTag(t *testing.T) {
+	custom := AuxTagInfo{Tag: 0x90, Name: "test", Kind: AuxValueUint}
+	if err := RegisterAuxTag(custom); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterAuxTag(custom.Tag) })
+
+	if err := RegisterAuxTag(custom); !errors.Is(err, errAuxTagTaken) {
+		t.Fatalf("expected errAuxTagTaken, got %v", err)
+	}
+
+	if err := RegisterAuxTag(AuxTagInfo{Tag: AuxTagEpoch, Name: "epoch"}); !errors.Is(err, errAuxTagReserved) {
+		t.Fatalf("expected errAuxTagReserved, got %v", err)
+	}
+
+	aux := NewAuxiliaryData()
+	if err := aux.SetUint64(custom.Tag, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeAuxiliaryData(aux.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, ok, err := decoded.Uint64(custom.Tag); err != nil || !ok || n != 7 {
+		t.Fatalf("Uint64() = %d, %v, %v", n, ok, err)
+	}
+}
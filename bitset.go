@@ -0,0 +1,197 @@
+package common
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// PartiesBitset is a fixed-size set of participant indexes, encoded like
+// TrackingID.PartiesState: bit i is bit (i % 8) of byte i / 8.
+type PartiesBitset struct {
+	bits []byte
+	size int
+}
+
+var (
+	errBitsetIndexOutOfRange = fmt.Errorf("bitset index out of range")
+	errBitsetNegativeSize    = fmt.Errorf("bitset size cannot be negative")
+)
+
+// NewPartiesBitset returns an empty bitset able to hold size participants.
+func NewPartiesBitset(size int) (*PartiesBitset, error) {
+	if size < 0 {
+		return nil, errBitsetNegativeSize
+	}
+
+	return &PartiesBitset{bits: make([]byte, (size+7)/8), size: size}, nil
+}
+
+// PartiesBitsetFromBytes wraps a copy of a PartiesState, its size is len(b)*8.
+func PartiesBitsetFromBytes(b []byte) *PartiesBitset {
+	return &PartiesBitset{bits: append([]byte{}, b...), size: len(b) * 8}
+}
+
+// PartiesBitsetFromBools packs bools into a bitset of size len(bools).
+func PartiesBitsetFromBools(bools []bool) *PartiesBitset {
+	return &PartiesBitset{bits: ConvertBoolArrayToByteArray(bools), size: len(bools)}
+}
+
+// Len returns the number of participants the bitset can hold.
+func (b *PartiesBitset) Len() int {
+	return b.size
+}
+
+func (b *PartiesBitset) checkIndex(i int) error {
+	if i < 0 || i >= b.size {
+		return fmt.Errorf("%w: index %d, size %d", errBitsetIndexOutOfRange, i, b.size)
+	}
+
+	return nil
+}
+
+// Set marks participant i.
+func (b *PartiesBitset) Set(i int) error {
+	if err := b.checkIndex(i); err != nil {
+		return err
+	}
+
+	b.bits[i/8] |= 1 << (i % 8)
+
+	return nil
+}
+
+// Clear unmarks participant i.
+func (b *PartiesBitset) Clear(i int) error {
+	if err := b.checkIndex(i); err != nil {
+		return err
+	}
+
+	b.bits[i/8] &^= 1 << (i % 8)
+
+	return nil
+}
+
+// Test reports whether participant i is marked.
+func (b *PartiesBitset) Test(i int) (bool, error) {
+	if err := b.checkIndex(i); err != nil {
+		return false, err
+	}
+
+	return b.bits[i/8]&(1<<(i%8)) != 0, nil
+}
+
+// Count returns the number of marked participants.
+func (b *PartiesBitset) Count() int {
+	count := 0
+	for _, v := range b.bits {
+		count += bits.OnesCount8(v)
+	}
+
+	return count
+}
+
+// Indices returns the marked participant indexes in ascending order.
+func (b *PartiesBitset) Indices() []int {
+	indices := make([]int, 0, b.Count())
+	b.ForEach(func(i int) {
+		indices = append(indices, i)
+	})
+
+	return indices
+}
+
+// ForEach calls fn for every marked participant in ascending order.
+func (b *PartiesBitset) ForEach(fn func(i int)) {
+	for byteIndex, v := range b.bits {
+		for v != 0 {
+			bit := bits.TrailingZeros8(v)
+			fn(byteIndex*8 + bit)
+			v &^= 1 << bit
+		}
+	}
+}
+
+// And returns the participants marked in both b and other.
+// The result has the size of the larger operand.
+func (b *PartiesBitset) And(other *PartiesBitset) *PartiesBitset {
+	return b.combine(other, func(x, y byte) byte { return x & y })
+}
+
+// Or returns the participants marked in b or other.
+// The result has the size of the larger operand.
+func (b *PartiesBitset) Or(other *PartiesBitset) *PartiesBitset {
+	return b.combine(other, func(x, y byte) byte { return x | y })
+}
+
+// AndNot returns the participants marked in b but not in other.
+// The result has the size of the larger operand.
+func (b *PartiesBitset) AndNot(other *PartiesBitset) *PartiesBitset {
+	return b.combine(other, func(x, y byte) byte { return x &^ y })
+}
+
+func (b *PartiesBitset) combine(other *PartiesBitset, op func(x, y byte) byte) *PartiesBitset {
+	size := max(b.size, other.size)
+	res := &PartiesBitset{bits: make([]byte, (size+7)/8), size: size}
+	for i := range res.bits {
+		var x, y byte
+		if i < len(b.bits) {
+			x = b.bits[i]
+		}
+
+		if i < len(other.bits) {
+			y = other.bits[i]
+		}
+
+		res.bits[i] = op(x, y)
+	}
+
+	return res
+}
+
+// Bytes returns the bitset encoded as a TrackingID.PartiesState.
+func (b *PartiesBitset) Bytes() []byte {
+	return append([]byte{}, b.bits...)
+}
+
+// Bools unpacks the bitset into a slice of length Len().
+func (b *PartiesBitset) Bools() []bool {
+	return ConvertByteArrayToBoolArray(b.bits, b.size)
+}
+
+// Equals reports whether both bitsets have the same size and marked participants.
+func (b *PartiesBitset) Equals(other *PartiesBitset) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+
+	if b.size != other.size {
+		return false
+	}
+
+	for i := range b.bits {
+		if b.bits[i] != other.bits[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PartiesBitset returns a copy of the PartiesState as a bitset.
+func (t *TrackingID) PartiesBitset() *PartiesBitset {
+	return PartiesBitsetFromBytes(t.GetPartiesState())
+}
+
+// SetPartiesBitset replaces the PartiesState with the content of b.
+func (t *TrackingID) SetPartiesBitset(b *PartiesBitset) {
+	t.PartiesState = b.Bytes()
+}
+
+// PartyState is the bounds-checked version of PartyStateOk.
+func (t *TrackingID) PartyState(i int) (bool, error) {
+	if t == nil {
+		return false, errNilTrackID
+	}
+
+	return t.PartiesBitset().Test(i)
+}
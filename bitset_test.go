@@ -0,0 +1,105 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPartiesBitset_SetClearTest(t *testing.T) {
+	b, err := NewPartiesBitset(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{0, 3, 9} {
+		if err := b.Set(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := b.Clear(3); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", b.Count())
+	}
+
+	if got := b.Indices(); len(got) != 2 || got[0] != 0 || got[1] != 9 {
+		t.Fatalf("Indices() = %v", got)
+	}
+
+	for _, i := range []int{-1, 10, 16} {
+		if err := b.Set(i); !errors.Is(err, errBitsetIndexOutOfRange) {
+			t.Fatalf("Set(%d) = %v, want errBitsetIndexOutOfRange", i, err)
+		}
+
+		if _, err := b.Test(i); !errors.Is(err, errBitsetIndexOutOfRange) {
+			t.Fatalf("Test(%d) = %v, want errBitsetIndexOutOfRange", i, err)
+		}
+	}
+
+	if !bytes.Equal(b.Bytes(), ConvertBoolArrayToByteArray(b.Bools())) {
+		t.Fatalf("Bytes() and Bools() disagree")
+	}
+}
+
+func TestPartiesBitset_Operations(t *testing.T) {
+	a := PartiesBitsetFromBools([]bool{true, true, false, true})
+	b := PartiesBitsetFromBools([]bool{false, true, true, true, false, false, false, false, true})
+
+	tests := []struct {
+		name string
+		got  *PartiesBitset
+		want []int
+	}{
+		{"And", a.And(b), []int{1, 3}},
+		{"Or", a.Or(b), []int{0, 1, 2, 3, 8}},
+		{"AndNot", a.AndNot(b), []int{0}},
+		{"AndNot reversed", b.AndNot(a), []int{2, 8}},
+	}
+
+	for _, tt := range tests {
+		got := tt.got.Indices()
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		if tt.got.Len() != 9 {
+			t.Fatalf("%s: Len() = %d, want 9", tt.name, tt.got.Len())
+		}
+	}
+}
+
+func TestTrackingID_PartiesBitset(t *testing.T) {
+	tid := &TrackingID{PartiesState: []byte{0x05}}
+
+	b := tid.PartiesBitset()
+	if err := b.Set(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if tid.PartiesState[0] != 0x05 {
+		t.Fatalf("PartiesBitset must return a copy")
+	}
+
+	tid.SetPartiesBitset(b)
+	if tid.PartiesState[0] != 0x07 {
+		t.Fatalf("SetPartiesBitset: got 0x%02x", tid.PartiesState[0])
+	}
+
+	if ok, err := tid.PartyState(2); err != nil || !ok {
+		t.Fatalf("PartyState(2) = %v, %v", ok, err)
+	}
+
+	if _, err := tid.PartyState(8); !errors.Is(err, errBitsetIndexOutOfRange) {
+		t.Fatalf("PartyState(8) = %v, want errBitsetIndexOutOfRange", err)
+	}
+}
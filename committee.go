@@ -0,0 +1,134 @@
+package common
+
+import (
+	"fmt"
+)
+
+// Committee is a sorted set of parties together with the threshold of the
+// protocol they run. Any threshold+1 members form a quorum.
+type Committee struct {
+	parties   SortedPartyIDs
+	threshold int
+	index     map[string]int
+}
+
+var (
+	errCommitteeEmpty         = fmt.Errorf("committee cannot be empty")
+	errCommitteeInvalidParty  = fmt.Errorf("committee contains an invalid PartyID")
+	errCommitteeDuplicate     = fmt.Errorf("committee contains a duplicate PartyID")
+	errCommitteeThreshold     = fmt.Errorf("committee threshold must satisfy 0 < t < n")
+	errNotInCommittee         = fmt.Errorf("party is not a member of the committee")
+	errSigningSetDuplicate    = fmt.Errorf("signing set contains a duplicate PartyID")
+	errSigningSetInsufficient = fmt.Errorf("signing set is smaller than the quorum")
+)
+
+// NewCommittee sorts and indexes the given parties, see IndexedPartyIDs, and validates them against the threshold.
+// Each PartyID must pass ValidateBasic, IDs must be unique and 0 < threshold < len(ids).
+func NewCommittee(ids UnSortedPartyIDs, threshold int) (*Committee, error) {
+	if len(ids) == 0 {
+		return nil, errCommitteeEmpty
+	}
+
+	if threshold <= 0 || threshold >= len(ids) {
+		return nil, fmt.Errorf("%w: t=%d, n=%d", errCommitteeThreshold, threshold, len(ids))
+	}
+
+	parties := IndexedPartyIDs(ids)
+	index := make(map[string]int, len(parties))
+	for i, pid := range parties {
+		if !pid.ValidateBasic() {
+			return nil, fmt.Errorf("%w at position %d", errCommitteeInvalidParty, i)
+		}
+
+		if _, ok := index[pid.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", errCommitteeDuplicate, pid.ID)
+		}
+
+		index[pid.ID] = i
+	}
+
+	return &Committee{
+		parties:   parties,
+		threshold: threshold,
+		index:     index,
+	}, nil
+}
+
+// Parties returns a copy of the sorted committee members.
+func (c *Committee) Parties() SortedPartyIDs {
+	cpy := make(SortedPartyIDs, len(c.parties))
+	copy(cpy, c.parties)
+
+	return cpy
+}
+
+// Threshold returns t, the maximal number of parties that may be corrupted.
+func (c *Committee) Threshold() int {
+	return c.threshold
+}
+
+// Size returns n, the number of committee members.
+func (c *Committee) Size() int {
+	return len(c.parties)
+}
+
+// QuorumSize returns the minimal number of parties (t+1) needed to run the protocol.
+func (c *Committee) QuorumSize() int {
+	return c.threshold + 1
+}
+
+// Index returns the position of pid in the sorted committee, or -1 if it is not a member.
+func (c *Committee) Index(pid *PartyID) int {
+	if pid == nil {
+		return -1
+	}
+
+	i, ok := c.index[pid.ID]
+	if !ok {
+		return -1
+	}
+
+	return i
+}
+
+// Contains reports whether pid is a member of the committee.
+func (c *Committee) Contains(pid *PartyID) bool {
+	return c.Index(pid) != -1
+}
+
+// Get returns the member at position i of the sorted committee, or nil if i is out of range.
+func (c *Committee) Get(i int) *PartyID {
+	if i < 0 || i >= len(c.parties) {
+		return nil
+	}
+
+	return c.parties[i]
+}
+
+// ValidateSigningSet checks that the parties are distinct committee members and
+// that there are at least QuorumSize of them.
+func (c *Committee) ValidateSigningSet(parties []*PartyID) error {
+	seen := make(map[string]struct{}, len(parties))
+	for _, pid := range parties {
+		if !c.Contains(pid) {
+			return fmt.Errorf("%w: %s", errNotInCommittee, pid.ToString())
+		}
+
+		if _, ok := seen[pid.ID]; ok {
+			return fmt.Errorf("%w: %s", errSigningSetDuplicate, pid.ID)
+		}
+
+		seen[pid.ID] = struct{}{}
+	}
+
+	if len(seen) < c.QuorumSize() {
+		return fmt.Errorf("%w: got %d, need %d", errSigningSetInsufficient, len(seen), c.QuorumSize())
+	}
+
+	return nil
+}
+
+// IsSigningSet reports whether the parties are distinct committee members forming a quorum.
+func (c *Committee) IsSigningSet(parties []*PartyID) bool {
+	return c.ValidateSigningSet(parties) == nil
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func partyIDs(ids ...string) UnSortedPartyIDs {
+	pids := make(UnSortedPartyIDs, len(ids))
+	for i, id := range ids {
+		pids[i] = &PartyID{ID: id}
+	}
+
+	return pids
+}
+
+func TestNewCommittee(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       UnSortedPartyIDs
+		threshold int
+		err       error
+	}{
+		{"valid", partyIDs("c", "a", "b"), 2, nil},
+		{"empty", nil, 1, errCommitteeEmpty},
+		{"threshold equals n", partyIDs("a", "b"), 2, errCommitteeThreshold},
+		{"zero threshold", partyIDs("a", "b"), 0, errCommitteeThreshold},
+		{"empty id", partyIDs("a", ""), 1, errCommitteeInvalidParty},
+		{"nil party", UnSortedPartyIDs{{ID: "a"}, nil}, 1, errCommitteeInvalidParty},
+		{"duplicate", partyIDs("a", "b", "a"), 1, errCommitteeDuplicate},
+	}
+
+	for _, tt := range tests {
+		_, err := NewCommittee(tt.ids, tt.threshold)
+		if tt.err == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: NewCommittee() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestCommittee_IndexAndSigningSet(t *testing.T) {
+	c, err := NewCommittee(partyIDs("c", "a", "d", "b"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if got := c.Index(&PartyID{ID: id}); got != i {
+			t.Fatalf("Index(%s) = %d, want %d", id, got, i)
+		}
+
+		if !c.Get(i).Equals(&PartyID{ID: id}) {
+			t.Fatalf("Get(%d) = %v, want %s", i, c.Get(i), id)
+		}
+	}
+
+	if c.Contains(&PartyID{ID: "e"}) || c.Contains(nil) {
+		t.Fatalf("Contains should be false for non-members")
+	}
+
+	tests := []struct {
+		name string
+		set  []*PartyID
+		err  error
+	}{
+		{"quorum", partyIDs("a", "c", "d"), nil},
+		{"all", partyIDs("a", "b", "c", "d"), nil},
+		{"too small", partyIDs("a", "b"), errSigningSetInsufficient},
+		{"duplicate", partyIDs("a", "b", "a"), errSigningSetDuplicate},
+		{"outsider", partyIDs("a", "b", "e"), errNotInCommittee},
+	}
+
+	for _, tt := range tests {
+		err := c.ValidateSigningSet(tt.set)
+		if tt.err == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: ValidateSigningSet() = %v, want %v", tt.name, err, tt.err)
+		}
+
+		if c.IsSigningSet(tt.set) != (tt.err == nil) {
+			t.Errorf("%s: IsSigningSet() disagrees with ValidateSigningSet()", tt.name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ContentAllowlist lists, per protocol, the content types a peer may send and the round each belongs to.
+// Parsing with an allowlist only instantiates listed types, see ParseOptions.
+type ContentAllowlist struct {
+	mtx   sync.RWMutex
+	types map[ProtocolType]map[protoreflect.FullName]int
+}
+
+var (
+	errAllowlistUnknownProtocol = fmt.Errorf("cannot allow content for an unregistered protocol")
+	errAllowlistNilContent      = fmt.Errorf("cannot allow nil content")
+	errAllowlistConflict        = fmt.Errorf("content type already allowed for a different round")
+	errContentNotAllowed        = fmt.Errorf("content type is not allowed for the protocol")
+	errContentRoundMismatch     = fmt.Errorf("content round does not match the allowed round of its type")
+)
+
+// NewContentAllowlist returns an empty allowlist, which rejects every content type.
+func NewContentAllowlist() *ContentAllowlist {
+	return &ContentAllowlist{types: map[ProtocolType]map[protoreflect.FullName]int{}}
+}
+
+// Allow permits content of the same type as the given message in the given round of the protocol.
+func (a *ContentAllowlist) Allow(protocol ProtocolType, round int, content proto.Message) error {
+	if !protocol.IsRegistered() {
+		return fmt.Errorf("%w: %s", errAllowlistUnknownProtocol, protocol)
+	}
+
+	if content == nil {
+		return errAllowlistNilContent
+	}
+
+	name := content.ProtoReflect().Descriptor().FullName()
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	types, ok := a.types[protocol]
+	if !ok {
+		types = map[protoreflect.FullName]int{}
+		a.types[protocol] = types
+	}
+
+	if existing, ok := types[name]; ok && existing != round {
+		return fmt.Errorf("%w: %s is allowed in round %d of %s", errAllowlistConflict, name, existing, protocol)
+	}
+
+	types[name] = round
+
+	return nil
+}
+
+// Round returns the round in which the type is allowed for the protocol.
+func (a *ContentAllowlist) Round(protocol ProtocolType, name protoreflect.FullName) (int, bool) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	round, ok := a.types[protocol][name]
+	return round, ok
+}
+
+// check returns the round of a content type, or an error if it is not allowed.
+func (a *ContentAllowlist) check(protocol ProtocolType, name protoreflect.FullName) (int, error) {
+	round, ok := a.Round(protocol, name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q for %s", errContentNotAllowed, name, protocol)
+	}
+
+	return round, nil
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func wireBytesWith(t *testing.T, protocol ProtocolType, content proto.Message, trackingID *TrackingID) []byte {
+	t.Helper()
+
+	msg, err := anypb.New(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bz, err := proto.Marshal(&MessageWrapper{Protocol: string(protocol), Message: msg, TrackingID: trackingID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bz
+}
+
+func TestContentAllowlist_Allow(t *testing.T) {
+	allowlist := NewContentAllowlist()
+
+	if err := allowlist.Allow(ProtocolECDSASign, 1, &SignatureData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := allowlist.Allow(ProtocolECDSASign, 1, &SignatureData{}); err != nil {
+		t.Fatalf("allowing the same round twice must succeed, got %v", err)
+	}
+
+	if err := allowlist.Allow(ProtocolECDSASign, 2, &SignatureData{}); !errors.Is(err, errAllowlistConflict) {
+		t.Fatalf("expected errAllowlistConflict, got %v", err)
+	}
+
+	if err := allowlist.Allow(ProtocolType("unknown"), 1, &SignatureData{}); !errors.Is(err, errAllowlistUnknownProtocol) {
+		t.Fatalf("expected errAllowlistUnknownProtocol, got %v", err)
+	}
+
+	if err := allowlist.Allow(ProtocolECDSASign, 1, nil); !errors.Is(err, errAllowlistNilContent) {
+		t.Fatalf("expected errAllowlistNilContent, got %v", err)
+	}
+
+	name := (&SignatureData{}).ProtoReflect().Descriptor().FullName()
+	if round, ok := allowlist.Round(ProtocolECDSASign, name); !ok || round != 1 {
+		t.Fatalf("expected round 1, got %d (%v)", round, ok)
+	}
+
+	if _, ok := allowlist.Round(ProtocolFROSTSign, name); ok {
+		t.Fatal("the type must only be allowed for the protocol it was registered for")
+	}
+}
+
+func TestParseWireMessageWithOptions_Allowlist(t *testing.T) {
+	from, to := NewPartyID("sender", "sender"), NewPartyID("receiver", "receiver")
+
+	allowlist := NewContentAllowlist()
+	if err := allowlist.Allow(ProtocolECDSASign, 1, &SignatureData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ParseOptions{Allowlist: allowlist}
+
+	t.Run("unexpected type", func(t *testing.T) {
+		trackingID := &TrackingID{Protocol: uint32(ProtocolECDSASign.ToInt()), Digest: make([]byte, 32)}
+		bz := wireBytesWith(t, ProtocolECDSASign, &PartyID{ID: "not content"}, trackingID)
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		if !errors.Is(err, errContentNotAllowed) {
+			t.Fatalf("expected errContentNotAllowed, got %v", err)
+		}
+
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *Error, got %T", err)
+		}
+
+		if len(perr.Culprits()) != 1 || perr.Culprits()[0] != from {
+			t.Fatalf("expected the sender as culprit, got %v", perr.Culprits())
+		}
+
+		if perr.Victim() != to {
+			t.Fatalf("expected the receiver as victim, got %v", perr.Victim())
+		}
+
+		if !perr.TrackingId().Equals(trackingID) {
+			t.Fatal("expected the error to carry the TrackingID")
+		}
+	})
+
+	t.Run("allowed for another protocol", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolFROSTSign, &SignatureData{}, nil)
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		if !errors.Is(err, errContentNotAllowed) {
+			t.Fatalf("expected errContentNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		bz, err := proto.Marshal(&MessageWrapper{Protocol: string(ProtocolECDSASign)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errParseNoContent) {
+			t.Fatalf("expected errParseNoContent, got %v", err)
+		}
+	})
+
+	t.Run("allowed type", func(t *testing.T) {
+		// SignatureData passes the allowlist, but is not MessageContent.
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, nil)
+
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+)
+
+var (
+	errDigestAlgorithm = fmt.Errorf("unknown TrackingID DigestAlgorithm")
+	errDigestLength    = fmt.Errorf("TrackingID Digest length does not match its DigestAlgorithm")
+)
+
+// DigestSize returns the exact size of digests produced by the algorithm, 0 for variable-size raw digests.
+// Legacy (unspecified) digests are 32 bytes.
+func (a DigestAlgorithm) DigestSize() int {
+	switch a {
+	case DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED, DigestAlgorithm_DIGEST_ALGORITHM_SHA256:
+		return sha256.Size
+	case DigestAlgorithm_DIGEST_ALGORITHM_SHA512:
+		return sha512.Size
+	default:
+		return 0
+	}
+}
+
+func (a DigestAlgorithm) isKnown() bool {
+	_, ok := DigestAlgorithm_name[int32(a)]
+	return ok
+}
+
+// DigestMessage computes the TrackingID digest of msg with the given algorithm.
+func DigestMessage(algorithm DigestAlgorithm, msg []byte) ([]byte, error) {
+	switch algorithm {
+	case DigestAlgorithm_DIGEST_ALGORITHM_RAW:
+		return append([]byte{}, msg...), nil
+	case DigestAlgorithm_DIGEST_ALGORITHM_SHA256:
+		d := sha256.Sum256(msg)
+		return d[:], nil
+	case DigestAlgorithm_DIGEST_ALGORITHM_SHA512:
+		d := sha512.Sum512(msg)
+		return d[:], nil
+	default:
+		return nil, fmt.Errorf("%w: %d", errDigestAlgorithm, algorithm)
+	}
+}
+
+// NewTrackingIDFromMessage is like NewTrackingID, but digests msg with the given algorithm
+// and records it in the TrackingID, so digests of any length are compared exactly.
+func NewTrackingIDFromMessage(protocol ProtocolType, algorithm DigestAlgorithm, msg []byte, committee SortedPartyIDs, healthy []*PartyID, auxiliaryData []byte) (*TrackingID, error) {
+	digest, err := DigestMessage(algorithm, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTrackingID(protocol, algorithm, digest, committee, healthy, auxiliaryData)
+}
+
+// validateDigest checks the length of a digest against its algorithm.
+func validateDigest(algorithm DigestAlgorithm, digest []byte, limits TrackingIDLimits) error {
+	if !algorithm.isKnown() {
+		return fmt.Errorf("%w: %d", errDigestAlgorithm, algorithm)
+	}
+
+	if len(digest) == 0 {
+		return errTrackidMustHaveDigest
+	}
+
+	if algorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+		if len(digest) != algorithm.DigestSize() {
+			return errTrackingIDigestLength
+		}
+
+		return nil
+	}
+
+	if size := algorithm.DigestSize(); size != 0 && len(digest) != size {
+		return fmt.Errorf("%w: %s digests are %d bytes, got %d", errDigestLength, algorithm, size, len(digest))
+	}
+
+	if limits.MaxDigestBytes > 0 && len(digest) > limits.MaxDigestBytes {
+		return fmt.Errorf("%w: Digest must be at most %d bytes", errTrackidPartTooLong, limits.MaxDigestBytes)
+	}
+
+	return nil
+}
+
+// canonicalDigest returns the Digest as compared by Equals: legacy digests are
+// zero-padded to 32 bytes, digests with an algorithm are used as-is.
+func (t *TrackingID) canonicalDigest() []byte {
+	if t.DigestAlgorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+		padded := pad32(t.Digest)
+		return padded[:]
+	}
+
+	return t.Digest
+}
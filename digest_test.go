@@ -0,0 +1,96 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTrackingID_VariableLengthDigests(t *testing.T) {
+	msg := []byte("a message signed as-is by FROST over Ed25519")
+
+	for _, alg := range []DigestAlgorithm{DigestAlgorithm_DIGEST_ALGORITHM_RAW, DigestAlgorithm_DIGEST_ALGORITHM_SHA256, DigestAlgorithm_DIGEST_ALGORITHM_SHA512} {
+		tid, err := NewTrackingIDFromMessage(ProtocolFROSTSign, alg, msg, nil, nil, []byte{1})
+		if err != nil {
+			t.Fatalf("%s: %v", alg, err)
+		}
+
+		s := tid.ToString()
+		if !strings.HasPrefix(s, "v3-") {
+			t.Fatalf("%s: expected a v3 string, got %q", alg, s)
+		}
+
+		var parsed TrackingID
+		if err := parsed.FromString(s); err != nil {
+			t.Fatalf("%s: FromString(ToString()) = %v", alg, err)
+		}
+
+		if !parsed.Equals(tid) || parsed.DigestAlgorithm != alg || !bytes.Equal(parsed.Digest, tid.Digest) {
+			t.Fatalf("%s: string round-trip mismatch", alg)
+		}
+
+		if err := parsed.FromCanonicalBytes(tid.CanonicalBytes()); err != nil || !parsed.Equals(tid) {
+			t.Fatalf("%s: canonical round-trip mismatch: %v", alg, err)
+		}
+	}
+
+	sha := sha512.Sum512(msg)
+	long, err := NewTrackingIDFromMessage(ProtocolFROSTSign, DigestAlgorithm_DIGEST_ALGORITHM_SHA512, msg, nil, nil, nil)
+	if err != nil || !bytes.Equal(long.Digest, sha[:]) {
+		t.Fatalf("SHA-512 digest mismatch: %v", err)
+	}
+
+	// 64-byte digests are compared exactly, not truncated to 32 bytes.
+	other := &TrackingID{Protocol: long.Protocol, DigestAlgorithm: long.DigestAlgorithm, Digest: append(append([]byte{}, sha[:32]...), make([]byte, 32)...)}
+	if other.Equals(long) {
+		t.Fatalf("Equals must compare the full digest")
+	}
+
+	// the algorithm is part of the identity.
+	raw := &TrackingID{Protocol: long.Protocol, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_RAW, Digest: long.Digest}
+	if raw.Equals(long) || raw.Key() == long.Key() {
+		t.Fatalf("digests with different algorithms must differ")
+	}
+
+	// trailing zeros are significant for exact digests.
+	rawZero := &TrackingID{Protocol: long.Protocol, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_RAW, Digest: append(append([]byte{}, long.Digest...), 0)}
+	if rawZero.Equals(raw) {
+		t.Fatalf("exact digests must not be zero-padded")
+	}
+}
+
+func TestTrackingID_DigestValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		tid  *TrackingID
+		err  error
+	}{
+		{"sha256 wrong size", &TrackingID{Protocol: 1, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_SHA256, Digest: make([]byte, 64)}, errDigestLength},
+		{"sha512 wrong size", &TrackingID{Protocol: 1, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_SHA512, Digest: make([]byte, 32)}, errDigestLength},
+		{"raw too long", &TrackingID{Protocol: 1, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_RAW, Digest: make([]byte, DefaultTrackingIDLimits.MaxDigestBytes+1)}, errTrackidPartTooLong},
+		{"raw empty", &TrackingID{Protocol: 1, DigestAlgorithm: DigestAlgorithm_DIGEST_ALGORITHM_RAW}, errTrackidMustHaveDigest},
+		{"unknown algorithm", &TrackingID{Protocol: 1, DigestAlgorithm: 42, Digest: []byte{1}}, errDigestAlgorithm},
+		{"legacy longer than 32", &TrackingID{Protocol: 1, Digest: make([]byte, 64)}, errTrackingIDigestLength},
+	}
+
+	for _, tt := range tests {
+		if err := tt.tid.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	var tid TrackingID
+	invalid := []string{
+		"v3-1-0-" + strings.Repeat("a", 64) + "--", // unspecified algorithm in v3
+		"v3-1-2-aaaa--", // sha256 with 2 bytes
+		"v3-1-2-" + strings.Repeat("a", 64) + "-", // missing part
+	}
+
+	for _, s := range invalid {
+		if err := tid.FromString(s); err == nil {
+			t.Errorf("FromString(%q) should fail", s)
+		}
+	}
+}
@@ -41,11 +41,11 @@ func (err *Error) Error() string {
 		return "Error is nil"
 	}
 	if err.culprits != nil && len(err.culprits) > 0 {
-		return fmt.Sprintf("task %s, party %v, round %d, culprits %s: %s",
-			err.task, err.victim, err.round, err.culprits, err.cause.Error())
+		return fmt.Sprintf("task %s, party %s, round %d, culprits %s: %s",
+			err.task, err.victim.DisplayName(), err.round, partyIDsString(err.culprits), err.cause.Error())
 	}
-	return fmt.Sprintf("task %s, party %v, round %d: %s",
-		err.task, err.victim, err.round, err.cause.Error())
+	return fmt.Sprintf("task %s, party %s, round %d: %s",
+		err.task, err.victim.DisplayName(), err.round, err.cause.Error())
 }
 
 func (err *Error) TrackingId() *TrackingID { return err.trackingId }
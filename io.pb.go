@@ -28,11 +28,129 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// KeyType defines the encoding of PartyID.public_key.
+type KeyType int32
+
+const (
+	KeyType_KEY_TYPE_UNSPECIFIED KeyType = 0
+	KeyType_KEY_TYPE_ED25519     KeyType = 1 // 32 bytes.
+	KeyType_KEY_TYPE_ECDSA_P256  KeyType = 2 // SEC 1 compressed (33 bytes) or uncompressed (65 bytes) point.
+)
+
+// Enum value maps for KeyType.
+var (
+	KeyType_name = map[int32]string{
+		0: "KEY_TYPE_UNSPECIFIED",
+		1: "KEY_TYPE_ED25519",
+		2: "KEY_TYPE_ECDSA_P256",
+	}
+	KeyType_value = map[string]int32{
+		"KEY_TYPE_UNSPECIFIED": 0,
+		"KEY_TYPE_ED25519":     1,
+		"KEY_TYPE_ECDSA_P256":  2,
+	}
+)
+
+func (x KeyType) Enum() *KeyType {
+	p := new(KeyType)
+	*p = x
+	return p
+}
+
+func (x KeyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_io_proto_enumTypes[0].Descriptor()
+}
+
+func (KeyType) Type() protoreflect.EnumType {
+	return &file_proto_io_proto_enumTypes[0]
+}
+
+func (x KeyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyType.Descriptor instead.
+func (KeyType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_io_proto_rawDescGZIP(), []int{0}
+}
+
+// DigestAlgorithm defines the content of TrackingID.digest.
+type DigestAlgorithm int32
+
+const (
+	// legacy digests of exactly 32 bytes, as required by TrackingID.Validate and FromString.
+	// Shorter digests built by hand are compared and written as if zero-padded to 32 bytes.
+	DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED DigestAlgorithm = 0
+	// the message itself, of any length.
+	DigestAlgorithm_DIGEST_ALGORITHM_RAW DigestAlgorithm = 1
+	// SHA-256 of the message, 32 bytes.
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA256 DigestAlgorithm = 2
+	// SHA-512 of the message, 64 bytes.
+	DigestAlgorithm_DIGEST_ALGORITHM_SHA512 DigestAlgorithm = 3
+)
+
+// Enum value maps for DigestAlgorithm.
+var (
+	DigestAlgorithm_name = map[int32]string{
+		0: "DIGEST_ALGORITHM_UNSPECIFIED",
+		1: "DIGEST_ALGORITHM_RAW",
+		2: "DIGEST_ALGORITHM_SHA256",
+		3: "DIGEST_ALGORITHM_SHA512",
+	}
+	DigestAlgorithm_value = map[string]int32{
+		"DIGEST_ALGORITHM_UNSPECIFIED": 0,
+		"DIGEST_ALGORITHM_RAW":         1,
+		"DIGEST_ALGORITHM_SHA256":      2,
+		"DIGEST_ALGORITHM_SHA512":      3,
+	}
+)
+
+func (x DigestAlgorithm) Enum() *DigestAlgorithm {
+	p := new(DigestAlgorithm)
+	*p = x
+	return p
+}
+
+func (x DigestAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DigestAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_io_proto_enumTypes[1].Descriptor()
+}
+
+func (DigestAlgorithm) Type() protoreflect.EnumType {
+	return &file_proto_io_proto_enumTypes[1]
+}
+
+func (x DigestAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DigestAlgorithm.Descriptor instead.
+func (DigestAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_proto_io_proto_rawDescGZIP(), []int{1}
+}
+
 // Using a struct in case we want to add more fields in the future
 // This is used to identify a party in the TSS protocol. Must be unique.
+// Only the ID takes part in equality; the other fields are optional metadata.
 type PartyID struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	ID    string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// public key used to authenticate the party, encoded according to key_type.
+	PublicKey []byte  `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	KeyType   KeyType `protobuf:"varint,3,opt,name=key_type,json=keyType,proto3,enum=xlabs.tsscommon.KeyType" json:"key_type,omitempty"`
+	// human-readable name of the party, used in logs and blame reports.
+	Moniker string `protobuf:"bytes,4,opt,name=moniker,proto3" json:"moniker,omitempty"`
+	// position of the party in its sorted committee, assigned by IndexedPartyIDs.
+	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	// X25519 public key used to seal messages sent to the party, 32 bytes.
+	EncryptionKey []byte `protobuf:"bytes,6,opt,name=encryption_key,json=encryptionKey,proto3" json:"encryption_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,6 +192,41 @@ func (x *PartyID) GetID() string {
 	return ""
 }
 
+func (x *PartyID) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *PartyID) GetKeyType() KeyType {
+	if x != nil {
+		return x.KeyType
+	}
+	return KeyType_KEY_TYPE_UNSPECIFIED
+}
+
+func (x *PartyID) GetMoniker() string {
+	if x != nil {
+		return x.Moniker
+	}
+	return ""
+}
+
+func (x *PartyID) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PartyID) GetEncryptionKey() []byte {
+	if x != nil {
+		return x.EncryptionKey
+	}
+	return nil
+}
+
 // Wrapper for TSS messages, often read by the transport layer and not itself sent over the wire
 type MessageWrapper struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -175,6 +328,160 @@ func (x *MessageWrapper) GetProtocol() string {
 	return ""
 }
 
+// SignedEnvelope authenticates a wire message, as produced by WireBytes, as sent by `from` to `to`.
+// The signature covers all other fields, see SignWireMessage.
+type SignedEnvelope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// serialized MessageWrapper.
+	Message []byte `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// IDs of the sender and of the recipient; the recipient is empty for broadcasts.
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// copies of the corresponding fields of the MessageWrapper.
+	TrackingId *TrackingID `protobuf:"bytes,4,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Protocol   string      `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// signature by the public key of the sender, see PartyID.key_type.
+	Signature     []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedEnvelope) Reset() {
+	*x = SignedEnvelope{}
+	mi := &file_proto_io_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedEnvelope) ProtoMessage() {}
+
+func (x *SignedEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_io_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedEnvelope.ProtoReflect.Descriptor instead.
+func (*SignedEnvelope) Descriptor() ([]byte, []int) {
+	return file_proto_io_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignedEnvelope) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *SignedEnvelope) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *SignedEnvelope) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *SignedEnvelope) GetTrackingId() *TrackingID {
+	if x != nil {
+		return x.TrackingId
+	}
+	return nil
+}
+
+func (x *SignedEnvelope) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *SignedEnvelope) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// SealedMessage holds a wire message encrypted to its recipient, see MessageImpl.WireBytes.
+type SealedMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ephemeral X25519 public key of the sender, 32 bytes.
+	EphemeralKey []byte `protobuf:"bytes,1,opt,name=ephemeral_key,json=ephemeralKey,proto3" json:"ephemeral_key,omitempty"`
+	// AES-256-GCM encryption of the serialized MessageWrapper.
+	Ciphertext []byte `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	// copy of the TrackingID of the MessageWrapper, authenticated with the ciphertext.
+	TrackingId    *TrackingID `protobuf:"bytes,3,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SealedMessage) Reset() {
+	*x = SealedMessage{}
+	mi := &file_proto_io_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SealedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SealedMessage) ProtoMessage() {}
+
+func (x *SealedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_io_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SealedMessage.ProtoReflect.Descriptor instead.
+func (*SealedMessage) Descriptor() ([]byte, []int) {
+	return file_proto_io_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SealedMessage) GetEphemeralKey() []byte {
+	if x != nil {
+		return x.EphemeralKey
+	}
+	return nil
+}
+
+func (x *SealedMessage) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *SealedMessage) GetTrackingId() *TrackingID {
+	if x != nil {
+		return x.TrackingId
+	}
+	return nil
+}
+
 // TrackingID is used to track the specific session when multiple sessions are running in parallel.
 // All messages tied to specific session should have the same TrackingID.
 type TrackingID struct {
@@ -188,13 +495,15 @@ type TrackingID struct {
 	PartiesState []byte `protobuf:"bytes,3,opt,name=parties_state,json=partiesState,proto3" json:"parties_state,omitempty"`
 	// any auxiliary data provided to the protocol from outside, and needs to be on every message.
 	AuxiliaryData []byte `protobuf:"bytes,4,opt,name=auxiliary_data,json=auxiliaryData,proto3" json:"auxiliary_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// how the digest was produced; defines its length and how digests are compared.
+	DigestAlgorithm DigestAlgorithm `protobuf:"varint,5,opt,name=digest_algorithm,json=digestAlgorithm,proto3,enum=xlabs.tsscommon.DigestAlgorithm" json:"digest_algorithm,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *TrackingID) Reset() {
 	*x = TrackingID{}
-	mi := &file_proto_io_proto_msgTypes[2]
+	mi := &file_proto_io_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -206,7 +515,7 @@ func (x *TrackingID) String() string {
 func (*TrackingID) ProtoMessage() {}
 
 func (x *TrackingID) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_io_proto_msgTypes[2]
+	mi := &file_proto_io_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -219,7 +528,7 @@ func (x *TrackingID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TrackingID.ProtoReflect.Descriptor instead.
 func (*TrackingID) Descriptor() ([]byte, []int) {
-	return file_proto_io_proto_rawDescGZIP(), []int{2}
+	return file_proto_io_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *TrackingID) GetProtocol() uint32 {
@@ -250,6 +559,13 @@ func (x *TrackingID) GetAuxiliaryData() []byte {
 	return nil
 }
 
+func (x *TrackingID) GetDigestAlgorithm() DigestAlgorithm {
+	if x != nil {
+		return x.DigestAlgorithm
+	}
+	return DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED
+}
+
 // Container for output signatures, mostly used for marshalling this data structure to a mobile app
 type SignatureData struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
@@ -270,7 +586,7 @@ type SignatureData struct {
 
 func (x *SignatureData) Reset() {
 	*x = SignatureData{}
-	mi := &file_proto_io_proto_msgTypes[3]
+	mi := &file_proto_io_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -282,7 +598,7 @@ func (x *SignatureData) String() string {
 func (*SignatureData) ProtoMessage() {}
 
 func (x *SignatureData) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_io_proto_msgTypes[3]
+	mi := &file_proto_io_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,7 +611,7 @@ func (x *SignatureData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SignatureData.ProtoReflect.Descriptor instead.
 func (*SignatureData) Descriptor() ([]byte, []int) {
-	return file_proto_io_proto_rawDescGZIP(), []int{3}
+	return file_proto_io_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SignatureData) GetSignature() []byte {
@@ -344,9 +660,15 @@ var File_proto_io_proto protoreflect.FileDescriptor
 
 const file_proto_io_proto_rawDesc = "" +
 	"\n" +
-	"\x0eproto/io.proto\x12\x0fxlabs.tsscommon\x1a\x19google/protobuf/any.proto\"\x19\n" +
+	"\x0eproto/io.proto\x12\x0fxlabs.tsscommon\x1a\x19google/protobuf/any.proto\"\xc4\x01\n" +
 	"\aPartyID\x12\x0e\n" +
-	"\x02ID\x18\x01 \x01(\tR\x02ID\"\xf3\x02\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\x123\n" +
+	"\bkey_type\x18\x03 \x01(\x0e2\x18.xlabs.tsscommon.KeyTypeR\akeyType\x12\x18\n" +
+	"\amoniker\x18\x04 \x01(\tR\amoniker\x12\x14\n" +
+	"\x05index\x18\x05 \x01(\rR\x05index\x12%\n" +
+	"\x0eencryption_key\x18\x06 \x01(\fR\rencryptionKey\"\xf3\x02\n" +
 	"\x0eMessageWrapper\x12-\n" +
 	"\x13is_to_old_committee\x18\x02 \x01(\bR\x10isToOldCommittee\x12=\n" +
 	"\x1cis_to_old_and_new_committees\x18\x05 \x01(\bR\x17isToOldAndNewCommittees\x12,\n" +
@@ -358,13 +680,29 @@ const file_proto_io_proto_rawDesc = "" +
 	"trackingID\x18\v \x01(\v2\x1b.xlabs.tsscommon.TrackingIDH\x00R\n" +
 	"trackingID\x88\x01\x01\x12\x1a\n" +
 	"\bProtocol\x18\f \x01(\tR\bProtocolB\r\n" +
-	"\v_trackingID\"\x8c\x01\n" +
+	"\v_trackingID\"\xc6\x01\n" +
+	"\x0eSignedEnvelope\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\fR\amessage\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12<\n" +
+	"\vtracking_id\x18\x04 \x01(\v2\x1b.xlabs.tsscommon.TrackingIDR\n" +
+	"trackingId\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\fR\tsignature\"\x92\x01\n" +
+	"\rSealedMessage\x12#\n" +
+	"\rephemeral_key\x18\x01 \x01(\fR\fephemeralKey\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\x12<\n" +
+	"\vtracking_id\x18\x03 \x01(\v2\x1b.xlabs.tsscommon.TrackingIDR\n" +
+	"trackingId\"\xd9\x01\n" +
 	"\n" +
 	"TrackingID\x12\x1a\n" +
 	"\bprotocol\x18\x01 \x01(\rR\bprotocol\x12\x16\n" +
 	"\x06digest\x18\x02 \x01(\fR\x06digest\x12#\n" +
 	"\rparties_state\x18\x03 \x01(\fR\fpartiesState\x12%\n" +
-	"\x0eauxiliary_data\x18\x04 \x01(\fR\rauxiliaryData\"\xc4\x01\n" +
+	"\x0eauxiliary_data\x18\x04 \x01(\fR\rauxiliaryData\x12K\n" +
+	"\x10digest_algorithm\x18\x05 \x01(\x0e2 .xlabs.tsscommon.DigestAlgorithmR\x0fdigestAlgorithm\"\xc4\x01\n" +
 	"\rSignatureData\x12\x1c\n" +
 	"\tsignature\x18\x01 \x01(\fR\tsignature\x12-\n" +
 	"\x12signature_recovery\x18\x02 \x01(\fR\x11signatureRecovery\x12\f\n" +
@@ -372,7 +710,16 @@ const file_proto_io_proto_rawDesc = "" +
 	"\x01s\x18\x04 \x01(\fR\x01s\x12\f\n" +
 	"\x01m\x18\x05 \x01(\fR\x01m\x12<\n" +
 	"\vtracking_id\x18\x06 \x01(\v2\x1b.xlabs.tsscommon.TrackingIDR\n" +
-	"trackingIdB\n" +
+	"trackingId*R\n" +
+	"\aKeyType\x12\x18\n" +
+	"\x14KEY_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10KEY_TYPE_ED25519\x10\x01\x12\x17\n" +
+	"\x13KEY_TYPE_ECDSA_P256\x10\x02*\x87\x01\n" +
+	"\x0fDigestAlgorithm\x12 \n" +
+	"\x1cDIGEST_ALGORITHM_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14DIGEST_ALGORITHM_RAW\x10\x01\x12\x1b\n" +
+	"\x17DIGEST_ALGORITHM_SHA256\x10\x02\x12\x1b\n" +
+	"\x17DIGEST_ALGORITHM_SHA512\x10\x03B\n" +
 	"Z\b./commonb\x06proto3"
 
 var (
@@ -387,25 +734,34 @@ func file_proto_io_proto_rawDescGZIP() []byte {
 	return file_proto_io_proto_rawDescData
 }
 
-var file_proto_io_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_io_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_io_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_proto_io_proto_goTypes = []any{
-	(*PartyID)(nil),        // 0: xlabs.tsscommon.PartyID
-	(*MessageWrapper)(nil), // 1: xlabs.tsscommon.MessageWrapper
-	(*TrackingID)(nil),     // 2: xlabs.tsscommon.TrackingID
-	(*SignatureData)(nil),  // 3: xlabs.tsscommon.SignatureData
-	(*anypb.Any)(nil),      // 4: google.protobuf.Any
+	(KeyType)(0),           // 0: xlabs.tsscommon.KeyType
+	(DigestAlgorithm)(0),   // 1: xlabs.tsscommon.DigestAlgorithm
+	(*PartyID)(nil),        // 2: xlabs.tsscommon.PartyID
+	(*MessageWrapper)(nil), // 3: xlabs.tsscommon.MessageWrapper
+	(*SignedEnvelope)(nil), // 4: xlabs.tsscommon.SignedEnvelope
+	(*SealedMessage)(nil),  // 5: xlabs.tsscommon.SealedMessage
+	(*TrackingID)(nil),     // 6: xlabs.tsscommon.TrackingID
+	(*SignatureData)(nil),  // 7: xlabs.tsscommon.SignatureData
+	(*anypb.Any)(nil),      // 8: google.protobuf.Any
 }
 var file_proto_io_proto_depIdxs = []int32{
-	0, // 0: xlabs.tsscommon.MessageWrapper.from:type_name -> xlabs.tsscommon.PartyID
-	0, // 1: xlabs.tsscommon.MessageWrapper.to:type_name -> xlabs.tsscommon.PartyID
-	4, // 2: xlabs.tsscommon.MessageWrapper.message:type_name -> google.protobuf.Any
-	2, // 3: xlabs.tsscommon.MessageWrapper.trackingID:type_name -> xlabs.tsscommon.TrackingID
-	2, // 4: xlabs.tsscommon.SignatureData.tracking_id:type_name -> xlabs.tsscommon.TrackingID
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0, // 0: xlabs.tsscommon.PartyID.key_type:type_name -> xlabs.tsscommon.KeyType
+	2, // 1: xlabs.tsscommon.MessageWrapper.from:type_name -> xlabs.tsscommon.PartyID
+	2, // 2: xlabs.tsscommon.MessageWrapper.to:type_name -> xlabs.tsscommon.PartyID
+	8, // 3: xlabs.tsscommon.MessageWrapper.message:type_name -> google.protobuf.Any
+	6, // 4: xlabs.tsscommon.MessageWrapper.trackingID:type_name -> xlabs.tsscommon.TrackingID
+	6, // 5: xlabs.tsscommon.SignedEnvelope.tracking_id:type_name -> xlabs.tsscommon.TrackingID
+	6, // 6: xlabs.tsscommon.SealedMessage.tracking_id:type_name -> xlabs.tsscommon.TrackingID
+	1, // 7: xlabs.tsscommon.TrackingID.digest_algorithm:type_name -> xlabs.tsscommon.DigestAlgorithm
+	6, // 8: xlabs.tsscommon.SignatureData.tracking_id:type_name -> xlabs.tsscommon.TrackingID
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_io_proto_init() }
@@ -419,13 +775,14 @@ func file_proto_io_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_io_proto_rawDesc), len(file_proto_io_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_proto_io_proto_goTypes,
 		DependencyIndexes: file_proto_io_proto_depIdxs,
+		EnumInfos:         file_proto_io_proto_enumTypes,
 		MessageInfos:      file_proto_io_proto_msgTypes,
 	}.Build()
 	File_proto_io_proto = out.File
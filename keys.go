@@ -0,0 +1,104 @@
+package common
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+var (
+	errUnsupportedKey = fmt.Errorf("unsupported public key, expected Ed25519 or ECDSA P-256")
+	errNoPublicKey    = fmt.Errorf("party has no public key")
+	errInvalidKey     = fmt.Errorf("invalid public key")
+)
+
+// EncodePublicKey returns the KeyType and encoding of an Ed25519 or ECDSA P-256 public key,
+// as held by PartyID. ECDSA keys are encoded as compressed points.
+func EncodePublicKey(pub crypto.PublicKey) (KeyType, []byte, error) {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return KeyType_KEY_TYPE_ED25519, append([]byte(nil), pub...), nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return KeyType_KEY_TYPE_UNSPECIFIED, nil, errUnsupportedKey
+		}
+
+		return KeyType_KEY_TYPE_ECDSA_P256, elliptic.MarshalCompressed(pub.Curve, pub.X, pub.Y), nil
+	default:
+		return KeyType_KEY_TYPE_UNSPECIFIED, nil, fmt.Errorf("%w: %T", errUnsupportedKey, pub)
+	}
+}
+
+// CryptoPublicKey decodes the public key of the party into an ed25519.PublicKey or an *ecdsa.PublicKey.
+func (pid *PartyID) CryptoPublicKey() (crypto.PublicKey, error) {
+	if !pid.HasPublicKey() {
+		return nil, errNoPublicKey
+	}
+
+	if !pid.validatePublicKey() {
+		return nil, fmt.Errorf("%w: %d bytes for %s", errInvalidKey, len(pid.PublicKey), pid.KeyType)
+	}
+
+	switch pid.KeyType {
+	case KeyType_KEY_TYPE_ED25519:
+		return ed25519.PublicKey(append([]byte(nil), pid.PublicKey...)), nil
+	case KeyType_KEY_TYPE_ECDSA_P256:
+		return decodeP256PublicKey(pid.PublicKey)
+	default:
+		return nil, errUnsupportedKey
+	}
+}
+
+func decodeP256PublicKey(b []byte) (*ecdsa.PublicKey, error) {
+	if len(b) == 1+2*32 {
+		// checks the point is on the curve.
+		if _, err := ecdh.P256().NewPublicKey(b); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidKey, err)
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(b[1:33]), Y: new(big.Int).SetBytes(b[33:])}, nil
+	}
+
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), b)
+	if x == nil {
+		return nil, errInvalidKey
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
+
+// signBytes signs the message with an Ed25519 key, or with an ECDSA P-256 key over its SHA-256 digest.
+// Other keys are rejected, as their signatures could not be verified against a PartyID.
+func signBytes(signer crypto.Signer, msg []byte) ([]byte, error) {
+	keyType, _, err := EncodePublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	switch keyType {
+	case KeyType_KEY_TYPE_ED25519:
+		return signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	default:
+		digest := sha256.Sum256(msg)
+		return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+}
+
+// verifyBytes checks a signature produced by signBytes.
+func verifyBytes(pub crypto.PublicKey, msg, sig []byte) bool {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, msg, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(msg)
+		return ecdsa.VerifyASN1(pub, digest[:], sig)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestEncodePublicKey(t *testing.T) {
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pub := range []any{edPub, &ecKey.PublicKey} {
+		keyType, bz, err := EncodePublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pid := NewPartyID("a", "").WithPublicKey(keyType, bz)
+		if !pid.ValidateBasic() {
+			t.Fatalf("expected a valid %s key", keyType)
+		}
+
+		decoded, err := pid.CryptoPublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !publicKeysEqual(decoded, pub) {
+			t.Fatalf("%s key does not round trip", keyType)
+		}
+	}
+
+	// uncompressed points are accepted as well.
+	ecdhKey, err := ecKey.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uncompressed := ecdhKey.Bytes()
+	decoded, err := NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ECDSA_P256, uncompressed).CryptoPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !publicKeysEqual(decoded, &ecKey.PublicKey) {
+		t.Fatal("uncompressed key does not round trip")
+	}
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := EncodePublicKey(&p384.PublicKey); !errors.Is(err, errUnsupportedKey) {
+		t.Fatalf("expected errUnsupportedKey, got %v", err)
+	}
+}
+
+func TestPartyID_CryptoPublicKeyInvalid(t *testing.T) {
+	if _, err := NewPartyID("a", "").CryptoPublicKey(); !errors.Is(err, errNoPublicKey) {
+		t.Fatalf("expected errNoPublicKey, got %v", err)
+	}
+
+	notOnCurve := make([]byte, 65)
+	notOnCurve[0] = 4
+	notOnCurve[64] = 1
+	if _, err := NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ECDSA_P256, notOnCurve).CryptoPublicKey(); !errors.Is(err, errInvalidKey) {
+		t.Fatalf("expected errInvalidKey, got %v", err)
+	}
+
+	if _, err := NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ED25519, make([]byte, 31)).CryptoPublicKey(); !errors.Is(err, errInvalidKey) {
+		t.Fatalf("expected errInvalidKey, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+const xCoordinateDomain = "tss-common/x-coordinate/v1"
+
+var (
+	errInvalidModulus        = fmt.Errorf("modulus must be greater than 1")
+	errXCoordinateZero       = fmt.Errorf("x-coordinate cannot be zero")
+	errXCoordinateRange      = fmt.Errorf("x-coordinate must be in range [1, modulus)")
+	errXCoordinateDuplicate  = fmt.Errorf("duplicate x-coordinate")
+	errLagrangeIndex         = fmt.Errorf("lagrange index out of range")
+	errLagrangeNotInvertible = fmt.Errorf("lagrange denominator is not invertible modulo q")
+	errNilPartyID            = fmt.Errorf("nil PartyID")
+)
+
+// PartyXCoordinate maps a PartyID to a non-zero element of Z/qZ by hashing its ID.
+func PartyXCoordinate(pid *PartyID, q *big.Int) (*big.Int, error) {
+	if !pid.ValidateBasic() {
+		return nil, errNilPartyID
+	}
+
+	if q == nil || q.Cmp(one) <= 0 {
+		return nil, errInvalidModulus
+	}
+
+	h := sha256.New()
+	h.Write([]byte(xCoordinateDomain))
+	h.Write([]byte(pid.ID))
+
+	x := new(big.Int).SetBytes(h.Sum(nil))
+	x.Mod(x, q)
+	if x.Sign() == 0 {
+		return nil, fmt.Errorf("%w: party %s", errXCoordinateZero, pid.ID)
+	}
+
+	return x, nil
+}
+
+// XCoordinatesFromIDs returns the hash-derived x-coordinate of each party, see PartyXCoordinate.
+// Returns an error if two parties map to the same coordinate.
+func XCoordinatesFromIDs(parties SortedPartyIDs, q *big.Int) ([]*big.Int, error) {
+	xs := make([]*big.Int, len(parties))
+	for i, pid := range parties {
+		x, err := PartyXCoordinate(pid, q)
+		if err != nil {
+			return nil, err
+		}
+
+		xs[i] = x
+	}
+
+	if err := ValidateXCoordinates(xs, q); err != nil {
+		return nil, err
+	}
+
+	return xs, nil
+}
+
+// XCoordinatesFromIndex returns i+1 as the x-coordinate of the party at index i of parties.
+func XCoordinatesFromIndex(parties SortedPartyIDs, q *big.Int) ([]*big.Int, error) {
+	if len(parties.Dedup()) != len(parties) {
+		return nil, errXCoordinateDuplicate
+	}
+
+	xs := make([]*big.Int, len(parties))
+	for i := range parties {
+		xs[i] = big.NewInt(int64(i + 1))
+	}
+
+	if err := ValidateXCoordinates(xs, q); err != nil {
+		return nil, err
+	}
+
+	return xs, nil
+}
+
+// ValidateXCoordinates checks that all coordinates are in [1, q) and pairwise distinct.
+func ValidateXCoordinates(xs []*big.Int, q *big.Int) error {
+	if q == nil || q.Cmp(one) <= 0 {
+		return errInvalidModulus
+	}
+
+	seen := make(map[string]struct{}, len(xs))
+	for i, x := range xs {
+		if x == nil || x.Sign() <= 0 || x.Cmp(q) >= 0 {
+			return fmt.Errorf("%w: index %d", errXCoordinateRange, i)
+		}
+
+		key := string(x.Bytes())
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("%w: index %d", errXCoordinateDuplicate, i)
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+// LagrangeCoefficient computes the Lagrange basis polynomial of xs[i] evaluated at `at`, modulo q:
+//
+//	λ_i(at) = Π_{j≠i} (at - x_j) / (x_i - x_j)
+//
+// q is expected to be prime (e.g. the order of the group).
+func LagrangeCoefficient(q *big.Int, xs []*big.Int, i int, at *big.Int) (*big.Int, error) {
+	if err := ValidateXCoordinates(xs, q); err != nil {
+		return nil, err
+	}
+
+	if i < 0 || i >= len(xs) {
+		return nil, errLagrangeIndex
+	}
+
+	mod := ModInt(q)
+	num, den := big.NewInt(1), big.NewInt(1)
+	for j, xj := range xs {
+		if j == i {
+			continue
+		}
+
+		num = mod.Mul(num, mod.Sub(at, xj))
+		den = mod.Mul(den, mod.Sub(xs[i], xj))
+	}
+
+	inv := mod.ModInverse(den)
+	if inv == nil {
+		return nil, errLagrangeNotInvertible
+	}
+
+	return mod.Mul(num, inv), nil
+}
+
+// LagrangeCoefficientAtZero computes λ_i(0), used to reconstruct a shared secret.
+func LagrangeCoefficientAtZero(q *big.Int, xs []*big.Int, i int) (*big.Int, error) {
+	return LagrangeCoefficient(q, xs, i, zero)
+}
+
+// LagrangeCoefficients computes λ_i(at) for every i in xs.
+func LagrangeCoefficients(q *big.Int, xs []*big.Int, at *big.Int) ([]*big.Int, error) {
+	coefs := make([]*big.Int, len(xs))
+	for i := range xs {
+		c, err := LagrangeCoefficient(q, xs, i, at)
+		if err != nil {
+			return nil, err
+		}
+
+		coefs[i] = c
+	}
+
+	return coefs, nil
+}
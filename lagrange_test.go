@@ -0,0 +1,82 @@
+package common
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// secp256k1 group order.
+var testOrder, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+func TestLagrangeCoefficients_Interpolate(t *testing.T) {
+	mod := ModInt(testOrder)
+	// f(x) = 5 + 3x + 7x^2
+	f := func(x *big.Int) *big.Int {
+		res := mod.Add(big.NewInt(5), mod.Mul(big.NewInt(3), x))
+		return mod.Add(res, mod.Mul(big.NewInt(7), mod.Mul(x, x)))
+	}
+
+	parties := SortPartyIDs(partyIDs("alice", "bob", "carol"))
+	xs, err := XCoordinatesFromIDs(parties, testOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, at := range []*big.Int{big.NewInt(0), big.NewInt(42)} {
+		coefs, err := LagrangeCoefficients(testOrder, xs, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sum := big.NewInt(0)
+		for i, c := range coefs {
+			sum = mod.Add(sum, mod.Mul(c, f(xs[i])))
+		}
+
+		if sum.Cmp(f(at)) != 0 {
+			t.Fatalf("interpolation at %v = %v, want %v", at, sum, f(at))
+		}
+	}
+
+	l0, err := LagrangeCoefficientAtZero(testOrder, xs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coefs, _ := LagrangeCoefficients(testOrder, xs, zero)
+	if l0.Cmp(coefs[0]) != 0 {
+		t.Fatalf("LagrangeCoefficientAtZero mismatch")
+	}
+}
+
+func TestXCoordinates(t *testing.T) {
+	parties := SortPartyIDs(partyIDs("a", "b", "c"))
+
+	xs, err := XCoordinatesFromIndex(parties, testOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, x := range xs {
+		if x.Int64() != int64(i+1) {
+			t.Fatalf("XCoordinatesFromIndex[%d] = %v", i, x)
+		}
+	}
+
+	if _, err := XCoordinatesFromIndex(SortPartyIDs(partyIDs("a", "a")), testOrder); !errors.Is(err, errXCoordinateDuplicate) {
+		t.Fatalf("expected errXCoordinateDuplicate, got %v", err)
+	}
+
+	if _, err := XCoordinatesFromIndex(parties, big.NewInt(3)); !errors.Is(err, errXCoordinateRange) {
+		t.Fatalf("expected errXCoordinateRange, got %v", err)
+	}
+
+	if err := ValidateXCoordinates([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(1)}, testOrder); !errors.Is(err, errXCoordinateDuplicate) {
+		t.Fatalf("expected errXCoordinateDuplicate, got %v", err)
+	}
+
+	if _, err := LagrangeCoefficient(testOrder, xs, 3, zero); !errors.Is(err, errLagrangeIndex) {
+		t.Fatalf("expected errLagrangeIndex, got %v", err)
+	}
+}
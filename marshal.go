@@ -0,0 +1,312 @@
+package common
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TrackingID, PartyID and SortedPartyIDs share one representation across text,
+// JSON and SQL:
+//   - TrackingID: the output of ToString, as a JSON string or SQL text.
+//   - PartyID: a JSON object holding its ID, moniker and keys, named as in the
+//     protobuf JSON mapping, also used as text and SQL text. The Index is assigned
+//     by IndexedPartyIDs and is not included. A JSON string is read as a bare ID.
+//   - SortedPartyIDs: a JSON array of PartyIDs, stored as SQL text.
+
+var (
+	_ encoding.TextMarshaler   = (*TrackingID)(nil)
+	_ encoding.TextUnmarshaler = (*TrackingID)(nil)
+	_ json.Marshaler           = (*TrackingID)(nil)
+	_ json.Unmarshaler         = (*TrackingID)(nil)
+	_ sql.Scanner              = (*TrackingID)(nil)
+	_ driver.Valuer            = (*TrackingID)(nil)
+
+	_ encoding.TextMarshaler   = (*PartyID)(nil)
+	_ encoding.TextUnmarshaler = (*PartyID)(nil)
+	_ json.Marshaler           = (*PartyID)(nil)
+	_ json.Unmarshaler         = (*PartyID)(nil)
+	_ sql.Scanner              = (*PartyID)(nil)
+	_ driver.Valuer            = (*PartyID)(nil)
+
+	_ encoding.TextMarshaler   = SortedPartyIDs(nil)
+	_ encoding.TextUnmarshaler = (*SortedPartyIDs)(nil)
+	_ json.Marshaler           = SortedPartyIDs(nil)
+	_ json.Unmarshaler         = (*SortedPartyIDs)(nil)
+	_ sql.Scanner              = (*SortedPartyIDs)(nil)
+	_ driver.Valuer            = SortedPartyIDs(nil)
+)
+
+var (
+	errScanType         = fmt.Errorf("unsupported type for Scan")
+	errEmptyPartyIDText = fmt.Errorf("PartyID text cannot be empty")
+)
+
+// scanText extracts the text of a database value, nil is reported with ok=false.
+func scanText(src any) (text []byte, ok bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []byte(v), true, nil
+	case []byte:
+		return v, true, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %T", errScanType, src)
+	}
+}
+
+// ----- TrackingID ----- //
+
+func (t *TrackingID) MarshalText() ([]byte, error) {
+	if t == nil {
+		return nil, errNilTrackID
+	}
+
+	return t.ToByteString(), nil
+}
+
+func (t *TrackingID) UnmarshalText(text []byte) error {
+	return t.FromString(string(text))
+}
+
+func (t *TrackingID) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.ToString())
+}
+
+func (t *TrackingID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return t.FromString(s)
+}
+
+// Scan implements sql.Scanner, a NULL value resets the TrackingID.
+func (t *TrackingID) Scan(src any) error {
+	text, ok, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		t.Reset()
+		return nil
+	}
+
+	return t.FromString(string(text))
+}
+
+// Value implements driver.Valuer, a nil TrackingID is stored as NULL.
+func (t *TrackingID) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	return t.ToString(), nil
+}
+
+// ----- PartyID ----- //
+
+// partyIDJSON is the representation of a PartyID, see the top of this file.
+type partyIDJSON struct {
+	ID            string `json:"ID"`
+	Moniker       string `json:"moniker,omitempty"`
+	KeyType       string `json:"keyType,omitempty"`
+	PublicKey     []byte `json:"publicKey,omitempty"`
+	EncryptionKey []byte `json:"encryptionKey,omitempty"`
+}
+
+func (pid *PartyID) MarshalText() ([]byte, error) {
+	if err := pid.validate(); err != nil {
+		return nil, &PartyIDError{Position: -1, ID: pid.GetID(), Reason: err}
+	}
+
+	v := partyIDJSON{
+		ID:            pid.ID,
+		Moniker:       pid.Moniker,
+		PublicKey:     pid.PublicKey,
+		EncryptionKey: pid.EncryptionKey,
+	}
+
+	if pid.KeyType != KeyType_KEY_TYPE_UNSPECIFIED {
+		v.KeyType = pid.KeyType.String()
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalText decodes the JSON representation of a PartyID, see UnmarshalJSON.
+func (pid *PartyID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errEmptyPartyIDText
+	}
+
+	return pid.UnmarshalJSON(text)
+}
+
+func (pid *PartyID) MarshalJSON() ([]byte, error) {
+	if pid == nil {
+		return []byte("null"), nil
+	}
+
+	return pid.MarshalText()
+}
+
+// UnmarshalJSON decodes a PartyID object or a bare ID string, and rejects parties that fail ValidateBasic.
+// null resets the PartyID.
+func (pid *PartyID) UnmarshalJSON(data []byte) error {
+	var v partyIDJSON
+
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.Equal(trimmed, []byte("null")):
+		pid.Reset()
+		return nil
+	case len(trimmed) > 0 && trimmed[0] == '"':
+		if err := json.Unmarshal(trimmed, &v.ID); err != nil {
+			return err
+		}
+
+		if v.ID == "" {
+			return errEmptyPartyIDText
+		}
+	default:
+		if err := json.Unmarshal(trimmed, &v); err != nil {
+			return err
+		}
+	}
+
+	decoded := &PartyID{
+		ID:            v.ID,
+		Moniker:       v.Moniker,
+		PublicKey:     v.PublicKey,
+		EncryptionKey: v.EncryptionKey,
+	}
+
+	if v.KeyType != "" {
+		keyType, ok := KeyType_value[v.KeyType]
+		if !ok {
+			return &PartyIDError{Position: -1, ID: v.ID, Reason: fmt.Errorf("%w: unknown key type %q", errPartyIDPublicKey, v.KeyType)}
+		}
+
+		decoded.KeyType = KeyType(keyType)
+	}
+
+	if err := decoded.validate(); err != nil {
+		return &PartyIDError{Position: -1, ID: v.ID, Reason: err}
+	}
+
+	pid.Reset()
+	proto.Merge(pid, decoded)
+
+	return nil
+}
+
+// Scan implements sql.Scanner, a NULL value resets the PartyID.
+func (pid *PartyID) Scan(src any) error {
+	text, ok, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		pid.Reset()
+		return nil
+	}
+
+	return pid.UnmarshalText(text)
+}
+
+// Value implements driver.Valuer, a nil PartyID is stored as NULL.
+func (pid *PartyID) Value() (driver.Value, error) {
+	if pid == nil {
+		return nil, nil
+	}
+
+	text, err := pid.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(text), nil
+}
+
+// ----- SortedPartyIDs ----- //
+
+func (spids SortedPartyIDs) MarshalJSON() ([]byte, error) {
+	if spids == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal([]*PartyID(spids))
+}
+
+// UnmarshalJSON decodes a JSON array of PartyIDs, which are sorted and indexed with IndexedPartyIDs.
+func (spids *SortedPartyIDs) UnmarshalJSON(data []byte) error {
+	var ids UnSortedPartyIDs
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+
+	if ids == nil {
+		*spids = nil
+		return nil
+	}
+
+	for i, pid := range ids {
+		if pid == nil {
+			return fmt.Errorf("%w at position %d", errNilPartyID, i)
+		}
+	}
+
+	*spids = IndexedPartyIDs(ids)
+
+	return nil
+}
+
+func (spids SortedPartyIDs) MarshalText() ([]byte, error) {
+	return spids.MarshalJSON()
+}
+
+func (spids *SortedPartyIDs) UnmarshalText(text []byte) error {
+	return spids.UnmarshalJSON(text)
+}
+
+// Scan implements sql.Scanner, a NULL value resets the list.
+func (spids *SortedPartyIDs) Scan(src any) error {
+	text, ok, err := scanText(src)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		*spids = nil
+		return nil
+	}
+
+	return spids.UnmarshalJSON(text)
+}
+
+// Value implements driver.Valuer, a nil list is stored as NULL.
+func (spids SortedPartyIDs) Value() (driver.Value, error) {
+	if spids == nil {
+		return nil, nil
+	}
+
+	text, err := spids.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return string(text), nil
+}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTrackingID_Marshaling(t *testing.T) {
+	tid := &TrackingID{Protocol: 1, Digest: bytes.Repeat([]byte{1}, 32), PartiesState: []byte{3}, AuxiliaryData: []byte{4}}
+
+	type row struct {
+		ID   *TrackingID `json:"id"`
+		Nil  *TrackingID `json:"nil"`
+		Name string      `json:"name"`
+	}
+
+	bz, err := json.Marshal(row{ID: tid, Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"` + tid.ToString() + `","nil":null,"name":"x"}`
+	if string(bz) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", bz, want)
+	}
+
+	var decoded row
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.ID.Equals(tid) || decoded.Nil != nil {
+		t.Fatalf("json round-trip mismatch: %+v", decoded)
+	}
+
+	v, err := tid.Value()
+	if err != nil || v != tid.ToString() {
+		t.Fatalf("Value() = %v, %v", v, err)
+	}
+
+	var scanned TrackingID
+	for _, src := range []any{v, []byte(v.(string))} {
+		if err := scanned.Scan(src); err != nil || !scanned.Equals(tid) {
+			t.Fatalf("Scan(%T) = %v", src, err)
+		}
+	}
+
+	if err := scanned.Scan(nil); err != nil || len(scanned.Digest) != 0 {
+		t.Fatalf("Scan(nil) should reset the TrackingID")
+	}
+
+	if err := scanned.Scan(42); !errors.Is(err, errScanType) {
+		t.Fatalf("Scan(int) = %v, want errScanType", err)
+	}
+
+	if err := scanned.UnmarshalText([]byte("garbage")); err == nil {
+		t.Fatalf("UnmarshalText should validate the TrackingID")
+	}
+}
+
+func TestPartyIDs_Marshaling(t *testing.T) {
+	pid := NewPartyID("a", "alice").WithPublicKey(KeyType_KEY_TYPE_ED25519, bytes.Repeat([]byte{1}, 32))
+	pid.EncryptionKey = bytes.Repeat([]byte{2}, 32)
+	pid.Index = 3
+
+	bz, err := json.Marshal(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded PartyID
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// every field but the Index, which SortPartyIDs assigns, is kept.
+	pid.Index = 0
+	if !proto.Equal(&decoded, pid) {
+		t.Fatalf("json.Unmarshal(PartyID) = %v, want %v", &decoded, pid)
+	}
+
+	// the representation follows the protobuf JSON mapping.
+	var fromProtoJSON PartyID
+	if err := protojson.Unmarshal(bz, &fromProtoJSON); err != nil || !proto.Equal(&fromProtoJSON, pid) {
+		t.Fatalf("protojson.Unmarshal(%s) = %v, %v", bz, &fromProtoJSON, err)
+	}
+
+	v, err := pid.Value()
+	if err != nil || v != string(bz) {
+		t.Fatalf("PartyID.Value() = %v, %v; want %s", v, err, bz)
+	}
+
+	var scannedPID PartyID
+	if err := scannedPID.Scan(v); err != nil || !proto.Equal(&scannedPID, pid) {
+		t.Fatalf("PartyID.Scan() = %v, %v", &scannedPID, err)
+	}
+
+	if err := json.Unmarshal([]byte(`"b"`), &decoded); err != nil || !proto.Equal(&decoded, &PartyID{ID: "b"}) {
+		t.Fatalf("a bare ID should decode as a PartyID without metadata, got %v, %v", &decoded, err)
+	}
+
+	if err := decoded.UnmarshalJSON([]byte("null")); err != nil || decoded.ID != "" {
+		t.Fatalf("null should reset the PartyID, got %v, %v", &decoded, err)
+	}
+
+	if err := json.Unmarshal([]byte(`""`), &decoded); !errors.Is(err, errEmptyPartyIDText) {
+		t.Fatalf("expected errEmptyPartyIDText, got %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ID":"a","keyType":"KEY_TYPE_ED25519","publicKey":"AQ=="}`), &decoded); !errors.Is(err, errPartyIDPublicKey) {
+		t.Fatalf("expected errPartyIDPublicKey, got %v", err)
+	}
+
+	invalid := NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ED25519, []byte{1})
+	if _, err := invalid.MarshalText(); !errors.Is(err, errPartyIDPublicKey) {
+		t.Fatalf("expected errPartyIDPublicKey, got %v", err)
+	}
+
+	spids := SortPartyIDs(partyIDs("c", "a", "b"))
+	v, err = spids.Value()
+	if err != nil || v != `[{"ID":"a"},{"ID":"b"},{"ID":"c"}]` {
+		t.Fatalf("SortedPartyIDs.Value() = %v, %v", v, err)
+	}
+
+	var scanned SortedPartyIDs
+	if err := scanned.Scan([]byte(`["b","c","a"]`)); err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "Scan", scanned, "a", "b", "c")
+
+	text, err := scanned.MarshalText()
+	if err != nil || string(text) != v {
+		t.Fatalf("MarshalText() = %s, want %s", text, v)
+	}
+
+	if err := scanned.UnmarshalText([]byte(`["a",null]`)); !errors.Is(err, errNilPartyID) {
+		t.Fatalf("expected errNilPartyID, got %v", err)
+	}
+}
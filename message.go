@@ -17,39 +17,44 @@ const (
 	ProtocolFROSTDKG  ProtocolType = "FROST:DKG"
 	ProtocolECDSASign ProtocolType = "ECDSA:SIGN"
 	ProtocolECDSADKG  ProtocolType = "ECDSA:DKG"
+
+	ProtocolECDSAReshare ProtocolType = "ECDSA:RESHARE"
+	ProtocolFROSTReshare ProtocolType = "FROST:RESHARE"
+	ProtocolECDSAPresign ProtocolType = "ECDSA:PRESIGN"
+	ProtocolECDSARefresh ProtocolType = "ECDSA:REFRESH"
 )
 
-// Integer protocol identifiers (useful for internal indexing, enums, etc.)
+// Integer protocol identifiers of the built-in protocols (useful for internal indexing, enums, etc.)
+// Additional protocols are added through RegisterProtocol.
 const (
 	protocolTypeMin = iota
 	protocolTypeFROSTSign
 	protocolTypeFROSTDKG
 	protocolTypeECDSASign
 	protocolTypeECDSADKG
-	protocolTypeMax
+	protocolTypeECDSAReshare
+	protocolTypeFROSTReshare
+	protocolTypeECDSAPresign
+	protocolTypeECDSARefresh
 )
 
 func (p ProtocolType) ToString() string {
 	return string(p)
 }
 
+// ToInt returns the integer identifier registered for p, or -1 if p is unknown.
 func (p ProtocolType) ToInt() int {
-	switch p {
-	case ProtocolFROSTSign:
-		return protocolTypeFROSTSign
-	case ProtocolFROSTDKG:
-		return protocolTypeFROSTDKG
-	case ProtocolECDSASign:
-		return protocolTypeECDSASign
-	case ProtocolECDSADKG:
-		return protocolTypeECDSADKG
-	default:
+	info, ok := LookupProtocol(p)
+	if !ok {
 		return -1
 	}
+
+	return info.ID
 }
 
 func isValidProtocolType(n int) bool {
-	return n > protocolTypeMin && n < protocolTypeMax
+	_, ok := LookupProtocolByID(n)
+	return ok
 }
 
 type (
@@ -103,6 +108,8 @@ type (
 		IsToOldCommittee bool
 		// whether the message should be sent to both old and new committee participants
 		IsToOldAndNewCommittees bool
+		// whether a unicast message must be sealed: WireBytes fails if `To` has no encryption key
+		RequireSealing bool
 	}
 
 	// Implements ParsedMessage; this is a concrete implementation of what messages produced by a LocalParty look like
@@ -128,6 +135,7 @@ var (
 
 // NewMessageWrapper constructs a MessageWrapper from routing metadata and content
 // digest is an additional parameter
+// The TrackingID is not validated, see NewMessageWrapperChecked.
 func NewMessageWrapper(routing MessageRouting, content MessageContent, trackingID ...*TrackingID) *MessageWrapper {
 	// marshal the content to the ProtoBuf Any type
 	anypbMsg, _ := anypb.New(content)
@@ -135,9 +143,7 @@ func NewMessageWrapper(routing MessageRouting, content MessageContent, trackingI
 	var to *PartyID = nil // broadcast
 	if routing.To != nil {
 		// direct message
-		to = &PartyID{
-			ID: routing.To.ID,
-		}
+		to = routing.To.Identity()
 	}
 
 	m := &MessageWrapper{
@@ -158,6 +164,18 @@ func NewMessageWrapper(routing MessageRouting, content MessageContent, trackingI
 	return m
 }
 
+// NewMessageWrapperChecked is like NewMessageWrapper, but rejects a TrackingID that fails TrackingID.Validate,
+// so that invalid IDs are caught before the message is put on the wire.
+func NewMessageWrapperChecked(routing MessageRouting, content MessageContent, trackingID ...*TrackingID) (*MessageWrapper, error) {
+	if len(trackingID) > 0 {
+		if err := trackingID[0].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid TrackingID: %w", err)
+		}
+	}
+
+	return NewMessageWrapper(routing, content, trackingID...), nil
+}
+
 // ----- //
 
 func NewMessage(meta MessageRouting, content MessageContent, wire *MessageWrapper) ParsedMessage {
@@ -195,6 +213,9 @@ func (mm *MessageImpl) IsToOldAndNewCommittees() bool {
 	return mm.wire.IsToOldAndNewCommittees
 }
 
+// WireBytes implements Message. Unicast messages to a party with an encryption key are sealed to it,
+// and must be parsed with its private key as ParseOptions.DecryptionKey. With RequireSealing,
+// unicast messages to a party without an encryption key fail instead of being sent in plaintext.
 func (mm *MessageImpl) WireBytes() ([]byte, *MessageRouting, error) {
 	tmp := proto.Clone(mm.wire).(*MessageWrapper)
 
@@ -207,6 +228,12 @@ func (mm *MessageImpl) WireBytes() ([]byte, *MessageRouting, error) {
 		return nil, nil, err
 	}
 
+	if !mm.IsBroadcast() && (mm.To.HasEncryptionKey() || mm.RequireSealing) {
+		if bz, err = sealWireBytes(bz, mm.From, mm.To, mm.wire.TrackingID); err != nil {
+			return nil, nil, fmt.Errorf("cannot seal message: %w", err)
+		}
+	}
+
 	return bz, &mm.MessageRouting, nil
 }
 
@@ -219,7 +246,21 @@ func (mm *MessageImpl) Content() MessageContent {
 }
 
 func (mm *MessageImpl) ValidateBasic() bool {
-	return mm.content.ValidateBasic()
+	return mm.content.ValidateBasic() && validateCommitteeFlags(mm.wire, mm.protocol)
+}
+
+// validateCommitteeFlags ensures the old/new committee routing flags are only
+// set for protocols that involve two committees (e.g. resharing).
+func validateCommitteeFlags(wire *MessageWrapper, protocol ProtocolType) bool {
+	if !wire.IsToOldCommittee && !wire.IsToOldAndNewCommittees {
+		return true
+	}
+
+	if wire.IsToOldCommittee && wire.IsToOldAndNewCommittees {
+		return false
+	}
+
+	return protocol.IsTwoCommittees()
 }
 
 func (mm *MessageImpl) String() string {
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"testing"
+)
+
+// testContent is a minimal MessageContent used across tests.
+type testContent struct {
+	*SignatureData
+	protocol ProtocolType
+	round    int
+}
+
+func (c *testContent) ValidateBasic() bool       { return c.SignatureData != nil }
+func (c *testContent) RoundNumber() int          { return c.round }
+func (c *testContent) GetProtocol() ProtocolType { return c.protocol }
+
+func newTestMessage(protocol ProtocolType, routing MessageRouting) ParsedMessage {
+	content := &testContent{SignatureData: &SignatureData{}, protocol: protocol}
+	return NewMessage(routing, content, NewMessageWrapper(routing, content))
+}
+
+func TestMessageImpl_ValidateCommitteeFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol ProtocolType
+		routing  MessageRouting
+		valid    bool
+	}{
+		{"sign without flags", ProtocolECDSASign, MessageRouting{}, true},
+		{"presign without flags", ProtocolECDSAPresign, MessageRouting{}, true},
+		{"sign to old committee", ProtocolECDSASign, MessageRouting{IsToOldCommittee: true}, false},
+		{"dkg to both committees", ProtocolFROSTDKG, MessageRouting{IsToOldAndNewCommittees: true}, false},
+		{"refresh to old committee", ProtocolECDSARefresh, MessageRouting{IsToOldCommittee: true}, false},
+		{"ecdsa reshare to old committee", ProtocolECDSAReshare, MessageRouting{IsToOldCommittee: true}, true},
+		{"frost reshare to both committees", ProtocolFROSTReshare, MessageRouting{IsToOldAndNewCommittees: true}, true},
+		{"reshare with both flags", ProtocolECDSAReshare, MessageRouting{IsToOldCommittee: true, IsToOldAndNewCommittees: true}, false},
+	}
+
+	for _, tt := range tests {
+		if got := newTestMessage(tt.protocol, tt.routing).ValidateBasic(); got != tt.valid {
+			t.Errorf("%s: ValidateBasic() = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestNewProtocolTrackingIDs(t *testing.T) {
+	for _, p := range []ProtocolType{ProtocolECDSAReshare, ProtocolFROSTReshare, ProtocolECDSAPresign, ProtocolECDSARefresh} {
+		id := p.ToInt()
+		if id <= 0 {
+			t.Fatalf("%s: ToInt() = %d", p, id)
+		}
+
+		got, err := (&TrackingID{Protocol: uint32(id)}).GetProtocolType()
+		if err != nil || got != p {
+			t.Fatalf("%s: GetProtocolType() = %q, %v", p, got, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"fmt"
+)
+
+var (
+	errPartiesStateLength    = fmt.Errorf("PartiesState length does not match the committee size")
+	errPartiesStateStrayBits = fmt.Errorf("PartiesState has bits set beyond the committee size")
+	errPartyNotInCommittee   = fmt.Errorf("healthy party is not a member of the committee")
+)
+
+// ValidatePartiesState checks that the PartiesState of the TrackingID holds exactly
+// one bit per member of a committee of the given size, i.e. it is (size+7)/8 bytes
+// long and no bit at index >= size is set.
+func (t *TrackingID) ValidatePartiesState(size int) error {
+	if t == nil {
+		return errNilTrackID
+	}
+
+	if want := (size + 7) / 8; len(t.PartiesState) != want {
+		return fmt.Errorf("%w: got %d bytes, want %d for %d parties", errPartiesStateLength, len(t.PartiesState), want, size)
+	}
+
+	for _, i := range t.PartiesBitset().Indices() {
+		if i >= size {
+			return fmt.Errorf("%w: bit %d set, %d parties", errPartiesStateStrayBits, i, size)
+		}
+	}
+
+	return nil
+}
+
+// PartitionParties splits the committee into the parties marked healthy and unhealthy
+// in the PartiesState of the TrackingID. Bit i refers to committee[i].
+func PartitionParties(committee SortedPartyIDs, trackingID *TrackingID) (healthy, unhealthy SortedPartyIDs, err error) {
+	if err := trackingID.ValidatePartiesState(len(committee)); err != nil {
+		return nil, nil, err
+	}
+
+	state := trackingID.PartiesBitset()
+	healthy = make(SortedPartyIDs, 0, len(committee))
+	unhealthy = make(SortedPartyIDs, 0)
+	for i, pid := range committee {
+		ok, err := state.Test(i)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ok {
+			healthy = append(healthy, pid)
+		} else {
+			unhealthy = append(unhealthy, pid)
+		}
+	}
+
+	return healthy, unhealthy, nil
+}
+
+// HealthyParties returns the committee members marked healthy in the PartiesState, see PartitionParties.
+func (t *TrackingID) HealthyParties(committee SortedPartyIDs) (SortedPartyIDs, error) {
+	healthy, _, err := PartitionParties(committee, t)
+	return healthy, err
+}
+
+// UnhealthyParties returns the committee members not marked healthy in the PartiesState, see PartitionParties.
+func (t *TrackingID) UnhealthyParties(committee SortedPartyIDs) (SortedPartyIDs, error) {
+	_, unhealthy, err := PartitionParties(committee, t)
+	return unhealthy, err
+}
+
+// NewPartiesState builds a PartiesState for the committee where the bit of each healthy party is set.
+// Every healthy party must be a member of the committee.
+func NewPartiesState(committee SortedPartyIDs, healthy []*PartyID) ([]byte, error) {
+	state, err := NewPartiesBitset(len(committee))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pid := range healthy {
+		i := committee.Find(pid)
+		if i == -1 {
+			return nil, fmt.Errorf("%w: %s", errPartyNotInCommittee, pid.ToString())
+		}
+
+		if err := state.Set(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return state.Bytes(), nil
+}
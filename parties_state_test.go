@@ -0,0 +1,58 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPartiesState_RoundTrip(t *testing.T) {
+	committee := SortPartyIDs(partyIDs("a", "b", "c", "d", "e", "f", "g", "h", "i"))
+
+	state, err := NewPartiesState(committee, partyIDs("i", "b", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(state) != 2 || state[0] != 0x06 || state[1] != 0x01 {
+		t.Fatalf("NewPartiesState() = %x", state)
+	}
+
+	tid := &TrackingID{PartiesState: state}
+	healthy, unhealthy, err := PartitionParties(committee, tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "healthy", healthy, "b", "c", "i")
+	assertIDs(t, "unhealthy", unhealthy, "a", "d", "e", "f", "g", "h")
+
+	if _, err := NewPartiesState(committee, partyIDs("z")); !errors.Is(err, errPartyNotInCommittee) {
+		t.Fatalf("expected errPartyNotInCommittee, got %v", err)
+	}
+}
+
+func TestPartiesState_Validation(t *testing.T) {
+	committee := SortPartyIDs(partyIDs("a", "b", "c"))
+
+	tests := []struct {
+		name  string
+		state []byte
+		err   error
+	}{
+		{"valid", []byte{0x07}, nil},
+		{"empty", nil, errPartiesStateLength},
+		{"too long", []byte{0x07, 0x00}, errPartiesStateLength},
+		{"stray bit", []byte{0x08}, errPartiesStateStrayBits},
+	}
+
+	for _, tt := range tests {
+		_, err := (&TrackingID{PartiesState: tt.state}).HealthyParties(committee)
+		if tt.err == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: HealthyParties() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -7,7 +7,11 @@
 package common
 
 import (
+	"fmt"
 	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
 )
 
 type (
@@ -16,8 +20,68 @@ type (
 	SortedPartyIDs []*PartyID
 )
 
+// NewPartyID creates a PartyID with the given ID and optional human-readable moniker.
+func NewPartyID(id, moniker string) *PartyID {
+	return &PartyID{ID: id, Moniker: moniker}
+}
+
+// WithPublicKey sets the public key of the party and returns it.
+func (pid *PartyID) WithPublicKey(keyType KeyType, publicKey []byte) *PartyID {
+	pid.KeyType = keyType
+	pid.PublicKey = append([]byte(nil), publicKey...)
+
+	return pid
+}
+
+// HasPublicKey reports whether the party carries a public key.
+func (pid *PartyID) HasPublicKey() bool {
+	return pid != nil && len(pid.PublicKey) > 0
+}
+
+// ValidateBasic checks the ID is set, that the public key, if any, matches its declared type,
+// and that the encryption key, if any, is an X25519 key.
 func (pid *PartyID) ValidateBasic() bool {
-	return pid != nil && pid.ID != ""
+	return pid.validate() == nil
+}
+
+// validate is like ValidateBasic, but reports which check failed.
+func (pid *PartyID) validate() error {
+	switch {
+	case pid == nil:
+		return errPartyIDNil
+	case pid.ID == "":
+		return errPartyIDEmpty
+	case !pid.validatePublicKey():
+		return fmt.Errorf("%w: %d bytes of %s", errPartyIDPublicKey, len(pid.PublicKey), pid.KeyType)
+	case len(pid.EncryptionKey) != 0 && len(pid.EncryptionKey) != x25519KeySize:
+		return fmt.Errorf("%w: %d bytes", errPartyIDEncryptionKey, len(pid.EncryptionKey))
+	}
+
+	return nil
+}
+
+func (pid *PartyID) validatePublicKey() bool {
+	if len(pid.PublicKey) == 0 {
+		return pid.KeyType == KeyType_KEY_TYPE_UNSPECIFIED
+	}
+
+	switch pid.KeyType {
+	case KeyType_KEY_TYPE_ED25519:
+		return len(pid.PublicKey) == 32
+	case KeyType_KEY_TYPE_ECDSA_P256:
+		return len(pid.PublicKey) == 33 || len(pid.PublicKey) == 65
+	default:
+		return false
+	}
+}
+
+// Identity returns a copy of the PartyID holding only its ID, which is all that is sent to peers on the wire.
+func (pid *PartyID) Identity() *PartyID {
+	if pid == nil {
+		return nil
+	}
+
+	return &PartyID{ID: pid.ID}
 }
 
 func (p *PartyID) Equals(other *PartyID) bool {
@@ -40,18 +104,62 @@ func (p *PartyID) ToString() string {
 	return p.GetID()
 }
 
-// SortPartyIDs sorts a list of []*PartyID by their keys in ascending order
+// DisplayName returns a human-readable representation of the party for logs and blame reports,
+// "moniker (ID)" when a moniker is set, the ID otherwise.
+func (p *PartyID) DisplayName() string {
+	if p == nil {
+		return "<nil>"
+	}
+
+	if p.GetMoniker() == "" {
+		return p.GetID()
+	}
+
+	return fmt.Sprintf("%s (%s)", p.GetMoniker(), p.GetID())
+}
+
+// SortPartyIDs sorts a list of []*PartyID by their keys in ascending order.
+// It returns the given pointers and leaves their Index untouched, see IndexedPartyIDs.
 func SortPartyIDs(ids UnSortedPartyIDs) SortedPartyIDs {
 	sorted := make(SortedPartyIDs, 0, len(ids))
 	for _, id := range ids {
 		sorted = append(sorted, id)
 	}
 	sort.Sort(sorted)
-	// assign party indexes
 
 	return sorted
 }
 
+// IndexedPartyIDs sorts the parties like SortPartyIDs, and returns copies of them whose Index is
+// their position in the sorted list. The given parties, which may be shared with other committees,
+// are left untouched.
+func IndexedPartyIDs(ids UnSortedPartyIDs) SortedPartyIDs {
+	sorted := SortPartyIDs(ids)
+	for i, id := range sorted {
+		if id != nil {
+			id = proto.Clone(id).(*PartyID)
+			id.Index = uint32(i)
+			sorted[i] = id
+		}
+	}
+
+	return sorted
+}
+
+// String returns the display names of the parties, e.g. "[alice (a) b]".
+func (spids SortedPartyIDs) String() string {
+	return partyIDsString(spids)
+}
+
+func partyIDsString(pids []*PartyID) string {
+	names := make([]string, len(pids))
+	for i, pid := range pids {
+		names[i] = pid.DisplayName()
+	}
+
+	return "[" + strings.Join(names, " ") + "]"
+}
+
 func (committee UnSortedPartyIDs) IsInCommittee(self *PartyID) bool {
 	return committee.IndexInCommittee(self) != -1
 }
@@ -66,6 +174,124 @@ func (committee UnSortedPartyIDs) IndexInCommittee(self *PartyID) int {
 	return -1
 }
 
+// Find returns the index of the party with the same ID as pid using binary search, or -1 if it is absent.
+func (spids SortedPartyIDs) Find(pid *PartyID) int {
+	if pid == nil {
+		return -1
+	}
+
+	i := sort.Search(len(spids), func(i int) bool {
+		return spids[i].GetID() >= pid.GetID()
+	})
+
+	if i < len(spids) && spids[i].GetID() == pid.GetID() {
+		return i
+	}
+
+	return -1
+}
+
+// Contains reports whether a party with the same ID as pid is in spids.
+func (spids SortedPartyIDs) Contains(pid *PartyID) bool {
+	return spids.Find(pid) != -1
+}
+
+// Dedup returns a copy of spids with repeated IDs removed.
+func (spids SortedPartyIDs) Dedup() SortedPartyIDs {
+	deduped := make(SortedPartyIDs, 0, len(spids))
+	for _, pid := range spids {
+		if len(deduped) > 0 && deduped[len(deduped)-1].GetID() == pid.GetID() {
+			continue
+		}
+
+		deduped = append(deduped, pid)
+	}
+
+	return deduped
+}
+
+// Union returns the sorted, deduplicated parties that are in spids or other.
+func (spids SortedPartyIDs) Union(other SortedPartyIDs) SortedPartyIDs {
+	union := make(SortedPartyIDs, 0, len(spids)+len(other))
+
+	i, j := 0, 0
+	for i < len(spids) && j < len(other) {
+		a, b := spids[i].GetID(), other[j].GetID()
+		switch {
+		case a < b:
+			union = append(union, spids[i])
+			i++
+		case a > b:
+			union = append(union, other[j])
+			j++
+		default:
+			union = append(union, spids[i])
+			i++
+			j++
+		}
+	}
+
+	union = append(union, spids[i:]...)
+	union = append(union, other[j:]...)
+
+	return union.Dedup()
+}
+
+// Intersect returns the sorted, deduplicated parties that are in both spids and other.
+func (spids SortedPartyIDs) Intersect(other SortedPartyIDs) SortedPartyIDs {
+	intersection := make(SortedPartyIDs, 0)
+
+	i, j := 0, 0
+	for i < len(spids) && j < len(other) {
+		a, b := spids[i].GetID(), other[j].GetID()
+		switch {
+		case a < b:
+			i++
+		case a > b:
+			j++
+		default:
+			intersection = append(intersection, spids[i])
+			i++
+			j++
+		}
+	}
+
+	return intersection.Dedup()
+}
+
+// Difference returns the sorted, deduplicated parties that are in spids but not in other.
+func (spids SortedPartyIDs) Difference(other SortedPartyIDs) SortedPartyIDs {
+	difference := make(SortedPartyIDs, 0, len(spids))
+
+	i, j := 0, 0
+	for i < len(spids) {
+		if j >= len(other) {
+			difference = append(difference, spids[i])
+			i++
+
+			continue
+		}
+
+		a, b := spids[i].GetID(), other[j].GetID()
+		switch {
+		case a < b:
+			difference = append(difference, spids[i])
+			i++
+		case a > b:
+			j++
+		default:
+			i++
+		}
+	}
+
+	return difference.Dedup()
+}
+
+// IsSubsetOf reports whether every party in spids is also in other.
+func (spids SortedPartyIDs) IsSubsetOf(other SortedPartyIDs) bool {
+	return len(spids.Difference(other)) == 0
+}
+
 // Sortable
 
 func (spids SortedPartyIDs) Len() int {
@@ -0,0 +1,178 @@
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func sortedIDs(spids SortedPartyIDs) []string {
+	ids := make([]string, len(spids))
+	for i, pid := range spids {
+		ids[i] = pid.GetID()
+	}
+
+	return ids
+}
+
+func assertIDs(t *testing.T, name string, got SortedPartyIDs, want ...string) {
+	t.Helper()
+
+	ids := sortedIDs(got)
+	if len(ids) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, ids, want)
+	}
+
+	for i := range ids {
+		if ids[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", name, ids, want)
+		}
+	}
+}
+
+func TestSortedPartyIDs_Find(t *testing.T) {
+	spids := SortPartyIDs(partyIDs("d", "b", "a", "c"))
+
+	for i, id := range []string{"a", "b", "c", "d"} {
+		if got := spids.Find(&PartyID{ID: id}); got != i {
+			t.Fatalf("Find(%s) = %d, want %d", id, got, i)
+		}
+	}
+
+	if spids.Find(&PartyID{ID: "e"}) != -1 || spids.Find(nil) != -1 || spids.Contains(&PartyID{ID: "0"}) {
+		t.Fatalf("Find should return -1 for absent parties")
+	}
+}
+
+func TestSortedPartyIDs_SetOperations(t *testing.T) {
+	a := SortPartyIDs(partyIDs("a", "b", "c", "c", "e"))
+	b := SortPartyIDs(partyIDs("b", "c", "d"))
+
+	assertIDs(t, "Dedup", a.Dedup(), "a", "b", "c", "e")
+	assertIDs(t, "Union", a.Union(b), "a", "b", "c", "d", "e")
+	assertIDs(t, "Intersect", a.Intersect(b), "b", "c")
+	assertIDs(t, "Difference", a.Difference(b), "a", "e")
+	assertIDs(t, "Difference", b.Difference(a), "d")
+	assertIDs(t, "Union with empty", a.Union(nil), "a", "b", "c", "e")
+	assertIDs(t, "Intersect with empty", a.Intersect(nil))
+
+	if !a.Intersect(b).IsSubsetOf(a) || !a.Intersect(b).IsSubsetOf(b) {
+		t.Fatalf("intersection should be a subset of both operands")
+	}
+
+	if a.IsSubsetOf(b) {
+		t.Fatalf("a should not be a subset of b")
+	}
+
+	if !SortedPartyIDs(nil).IsSubsetOf(a) {
+		t.Fatalf("empty set should be a subset of any set")
+	}
+}
+
+func TestPartyID_Metadata(t *testing.T) {
+	withKey := NewPartyID("a", "alice").WithPublicKey(KeyType_KEY_TYPE_ED25519, make([]byte, 32))
+	if !withKey.ValidateBasic() || !withKey.HasPublicKey() {
+		t.Fatalf("expected valid PartyID with public key")
+	}
+
+	if !withKey.Equals(&PartyID{ID: "a"}) {
+		t.Fatalf("Equals must only consider the ID")
+	}
+
+	if got := withKey.DisplayName(); got != "alice (a)" {
+		t.Fatalf("DisplayName() = %q", got)
+	}
+
+	if got := (&PartyID{ID: "b"}).DisplayName(); got != "b" {
+		t.Fatalf("DisplayName() = %q", got)
+	}
+
+	invalid := []*PartyID{
+		NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ED25519, make([]byte, 31)),
+		NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_ECDSA_P256, make([]byte, 32)),
+		NewPartyID("a", "").WithPublicKey(KeyType_KEY_TYPE_UNSPECIFIED, make([]byte, 32)),
+		{ID: "a", KeyType: KeyType_KEY_TYPE_ED25519},
+	}
+	for i, pid := range invalid {
+		if pid.ValidateBasic() {
+			t.Errorf("case %d: expected ValidateBasic() to fail", i)
+		}
+	}
+}
+
+func TestPartyID_WireCompatibility(t *testing.T) {
+	full := NewPartyID("a", "alice").WithPublicKey(KeyType_KEY_TYPE_ECDSA_P256, make([]byte, 33))
+	full.Index = 3
+
+	// peers that only know the ID field see the same encoding as before for Identity().
+	bz, err := proto.Marshal(full.Identity())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bz, append([]byte{0x0a, 0x01}, 'a')) {
+		t.Fatalf("unexpected ID-only encoding %x", bz)
+	}
+
+	var decoded PartyID
+	if err := proto.Unmarshal(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equals(full) || decoded.HasPublicKey() {
+		t.Fatalf("decoded PartyID mismatch: %v", &decoded)
+	}
+}
+
+func TestSortPartyIDs_KeepsPointers(t *testing.T) {
+	parties := partyIDs("c", "a", "b")
+	spids := SortPartyIDs(parties)
+	if spids[0] != parties[1] || spids[1] != parties[2] || spids[2] != parties[0] {
+		t.Fatal("SortPartyIDs must return the given pointers")
+	}
+
+	for _, pid := range spids {
+		if pid.Index != 0 {
+			t.Fatalf("SortPartyIDs must not assign indexes, %s has index %d", pid.ID, pid.Index)
+		}
+	}
+}
+
+func TestIndexedPartyIDs(t *testing.T) {
+	parties := partyIDs("c", "a", "b")
+	spids := IndexedPartyIDs(parties)
+	assertIDs(t, "IndexedPartyIDs", spids, "a", "b", "c")
+
+	for i, pid := range spids {
+		if pid.Index != uint32(i) {
+			t.Fatalf("party %s has index %d, want %d", pid.ID, pid.Index, i)
+		}
+	}
+
+	if parties[0].Index != 0 || parties[2].Index != 0 {
+		t.Fatal("IndexedPartyIDs must not modify the given parties")
+	}
+}
+
+func TestSortPartyIDs_SharedParties(t *testing.T) {
+	parties := partyIDs("a", "b", "c", "d")
+	a, b, c, d := parties[0], parties[1], parties[2], parties[3]
+
+	oldCommittee, err := NewCommittee(UnSortedPartyIDs{a, b, c}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewCommittee(UnSortedPartyIDs{c, d}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := oldCommittee.Get(2); got.ID != "c" || got.Index != 2 {
+		t.Fatalf("old committee party %s has index %d, want c at 2", got.ID, got.Index)
+	}
+
+	if c.Index != 0 || d.Index != 0 {
+		t.Fatal("NewCommittee must not modify the given parties")
+	}
+}
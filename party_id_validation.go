@@ -0,0 +1,184 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	errPartyIDNil           = fmt.Errorf("PartyID is nil")
+	errPartyIDEmpty         = fmt.Errorf("PartyID has an empty ID")
+	errPartyIDTooLong       = fmt.Errorf("PartyID is too long")
+	errPartyIDInvalidUTF8   = fmt.Errorf("PartyID is not valid UTF-8")
+	errPartyIDInvalidChar   = fmt.Errorf("PartyID contains a disallowed character")
+	errPartyIDDash          = fmt.Errorf("PartyID contains '-'")
+	errPartyIDWhitespace    = fmt.Errorf("PartyID has leading or trailing whitespace")
+	errPartyIDPublicKey     = fmt.Errorf("PartyID public key does not match its key type")
+	errPartyIDEncryptionKey = fmt.Errorf("PartyID encryption key is not an X25519 key")
+	errPartyIDDuplicate     = fmt.Errorf("PartyID appears more than once in the committee")
+	errCommitteeNoParties   = fmt.Errorf("committee has no parties")
+)
+
+// PartyIDPolicy defines format rules for PartyID.ID, applied on top of ValidateBasic.
+type PartyIDPolicy struct {
+	// MaxLength is the maximal length of the ID in bytes, 0 means unlimited.
+	MaxLength int
+	// AllowedRune reports whether a character may appear in the ID, nil allows any printable character,
+	// as defined by unicode.IsPrint: the only whitespace allowed is the ASCII space.
+	// IDs that are not valid UTF-8 are always rejected.
+	AllowedRune func(r rune) bool
+	// AllowDash allows '-' in the ID, which is otherwise rejected since it is the separator of TrackingID strings.
+	AllowDash bool
+	// AllowSurroundingWhitespace allows leading and trailing whitespace.
+	AllowSurroundingWhitespace bool
+}
+
+// DefaultPartyIDPolicy allows printable IDs of at most 256 bytes, without '-' and surrounding whitespace.
+var DefaultPartyIDPolicy = PartyIDPolicy{
+	MaxLength: 256,
+}
+
+// PartyIDError reports why a PartyID, at a given position of a committee, is invalid.
+type PartyIDError struct {
+	// Position in the validated list, -1 when validating a single PartyID.
+	Position int
+	ID       string
+	Reason   error
+}
+
+func (e *PartyIDError) Error() string {
+	if e.Position < 0 {
+		return fmt.Sprintf("party %q: %s", e.ID, e.Reason)
+	}
+
+	return fmt.Sprintf("party %q at position %d: %s", e.ID, e.Position, e.Reason)
+}
+
+func (e *PartyIDError) Unwrap() error { return e.Reason }
+
+// CommitteeValidationError lists every problem found while validating a committee.
+type CommitteeValidationError struct {
+	Problems []*PartyIDError
+}
+
+func (e *CommitteeValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+
+	return fmt.Sprintf("invalid committee, %d problem(s): %s", len(e.Problems), strings.Join(msgs, "; "))
+}
+
+// Unwrap allows errors.Is and errors.As to match any of the problems.
+func (e *CommitteeValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p
+	}
+
+	return errs
+}
+
+// Validate checks pid against the policy and returns a *PartyIDError on the first violated rule.
+func (policy PartyIDPolicy) Validate(pid *PartyID) error {
+	if reason := policy.check(pid); reason != nil {
+		return &PartyIDError{Position: -1, ID: pid.GetID(), Reason: reason}
+	}
+
+	return nil
+}
+
+func (policy PartyIDPolicy) check(pid *PartyID) error {
+	if pid == nil {
+		return errPartyIDNil
+	}
+
+	id := pid.GetID()
+	if id == "" {
+		return errPartyIDEmpty
+	}
+
+	if policy.MaxLength > 0 && len(id) > policy.MaxLength {
+		return fmt.Errorf("%w: %d bytes, at most %d", errPartyIDTooLong, len(id), policy.MaxLength)
+	}
+
+	if !policy.AllowSurroundingWhitespace && strings.TrimSpace(id) != id {
+		return errPartyIDWhitespace
+	}
+
+	if !policy.AllowDash && strings.Contains(id, "-") {
+		return errPartyIDDash
+	}
+
+	if !utf8.ValidString(id) {
+		return errPartyIDInvalidUTF8
+	}
+
+	for _, r := range id {
+		if r == '-' && policy.AllowDash {
+			continue
+		}
+
+		allowed := unicode.IsPrint(r)
+		if policy.AllowedRune != nil {
+			allowed = policy.AllowedRune(r)
+		}
+
+		if !allowed {
+			return fmt.Errorf("%w: %q", errPartyIDInvalidChar, r)
+		}
+	}
+
+	if !pid.validatePublicKey() {
+		return errPartyIDPublicKey
+	}
+
+	return nil
+}
+
+// ValidateCommittee checks every party against the policy and reports duplicate IDs.
+// All problems are returned at once in a *CommitteeValidationError.
+func (policy PartyIDPolicy) ValidateCommittee(ids UnSortedPartyIDs) error {
+	if len(ids) == 0 {
+		return &CommitteeValidationError{Problems: []*PartyIDError{{Position: -1, Reason: errCommitteeNoParties}}}
+	}
+
+	var problems []*PartyIDError
+
+	firstSeen := make(map[string]int, len(ids))
+	for i, pid := range ids {
+		if reason := policy.check(pid); reason != nil {
+			problems = append(problems, &PartyIDError{Position: i, ID: pid.GetID(), Reason: reason})
+		}
+
+		if pid.GetID() == "" {
+			continue
+		}
+
+		if first, ok := firstSeen[pid.GetID()]; ok {
+			problems = append(problems, &PartyIDError{
+				Position: i,
+				ID:       pid.GetID(),
+				Reason:   fmt.Errorf("%w, first at position %d", errPartyIDDuplicate, first),
+			})
+
+			continue
+		}
+
+		firstSeen[pid.GetID()] = i
+	}
+
+	if len(problems) > 0 {
+		return &CommitteeValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// Validate checks the committee against DefaultPartyIDPolicy, see PartyIDPolicy.ValidateCommittee.
+func (committee UnSortedPartyIDs) Validate() error {
+	return DefaultPartyIDPolicy.ValidateCommittee(committee)
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPartyIDPolicy_Validate(t *testing.T) {
+	alnum := PartyIDPolicy{
+		MaxLength: 8,
+		AllowedRune: func(r rune) bool {
+			return ('a' <= r && r <= 'z') || ('0' <= r && r <= '9')
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy PartyIDPolicy
+		pid    *PartyID
+		err    error
+	}{
+		{"valid", DefaultPartyIDPolicy, &PartyID{ID: "node_1"}, nil},
+		{"nil", DefaultPartyIDPolicy, nil, errPartyIDNil},
+		{"empty", DefaultPartyIDPolicy, &PartyID{}, errPartyIDEmpty},
+		{"dash", DefaultPartyIDPolicy, &PartyID{ID: "node-1"}, errPartyIDDash},
+		{"dash allowed", PartyIDPolicy{AllowDash: true}, &PartyID{ID: "node-1"}, nil},
+		{"leading space", DefaultPartyIDPolicy, &PartyID{ID: " node"}, errPartyIDWhitespace},
+		{"trailing newline", DefaultPartyIDPolicy, &PartyID{ID: "node\n"}, errPartyIDWhitespace},
+		{"interior space", DefaultPartyIDPolicy, &PartyID{ID: "node 1"}, nil},
+		{"interior newline", DefaultPartyIDPolicy, &PartyID{ID: "node\n1"}, errPartyIDInvalidChar},
+		{"interior carriage return", DefaultPartyIDPolicy, &PartyID{ID: "node\r1"}, errPartyIDInvalidChar},
+		{"interior tab", DefaultPartyIDPolicy, &PartyID{ID: "node\t1"}, errPartyIDInvalidChar},
+		{"control character", DefaultPartyIDPolicy, &PartyID{ID: "no\x00de"}, errPartyIDInvalidChar},
+		{"invalid UTF-8", DefaultPartyIDPolicy, &PartyID{ID: "no\xffde"}, errPartyIDInvalidUTF8},
+		{"invalid UTF-8 with custom charset", PartyIDPolicy{AllowedRune: func(rune) bool { return true }}, &PartyID{ID: "\xc3"}, errPartyIDInvalidUTF8},
+		{"too long", DefaultPartyIDPolicy, &PartyID{ID: strings.Repeat("a", 257)}, errPartyIDTooLong},
+		{"charset", alnum, &PartyID{ID: "Node"}, errPartyIDInvalidChar},
+		{"charset max length", alnum, &PartyID{ID: "node12345"}, errPartyIDTooLong},
+		{"bad public key", DefaultPartyIDPolicy, &PartyID{ID: "a", KeyType: KeyType_KEY_TYPE_ED25519, PublicKey: []byte{1}}, errPartyIDPublicKey},
+	}
+
+	for _, tt := range tests {
+		err := tt.policy.Validate(tt.pid)
+		if tt.err == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+
+			continue
+		}
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+
+		var pidErr *PartyIDError
+		if !errors.As(err, &pidErr) {
+			t.Errorf("%s: expected a *PartyIDError, got %T", tt.name, err)
+		}
+	}
+}
+
+func TestPartyIDPolicy_ValidateCommittee(t *testing.T) {
+	if err := partyIDs("a", "b", "c").Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	err := partyIDs("a", "b-c", "a", "", "b-c").Validate()
+
+	var committeeErr *CommitteeValidationError
+	if !errors.As(err, &committeeErr) {
+		t.Fatalf("expected *CommitteeValidationError, got %v", err)
+	}
+
+	// b-c (dash), a (duplicate), "" (empty), b-c (dash and duplicate)
+	if len(committeeErr.Problems) != 5 {
+		t.Fatalf("expected 5 problems, got %d: %v", len(committeeErr.Problems), err)
+	}
+
+	for _, want := range []error{errPartyIDDash, errPartyIDDuplicate, errPartyIDEmpty} {
+		if !errors.Is(err, want) {
+			t.Errorf("expected errors.Is(err, %v)", want)
+		}
+	}
+
+	if committeeErr.Problems[1].Position != 2 || committeeErr.Problems[1].ID != "a" {
+		t.Errorf("unexpected duplicate report %v", committeeErr.Problems[1])
+	}
+
+	if err := UnSortedPartyIDs(nil).Validate(); !errors.Is(err, errCommitteeNoParties) {
+		t.Fatalf("expected errCommitteeNoParties, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Polynomial is a polynomial over Z/qZ, coefficients are stored from the constant term upwards.
+type Polynomial struct {
+	q            *big.Int
+	coefficients []*big.Int
+}
+
+// SecretShare is the evaluation of a sharing polynomial at the x-coordinate of a party.
+type SecretShare struct {
+	ID    *PartyID
+	X     *big.Int
+	Value *big.Int
+}
+
+var (
+	errPolynomialDegree     = fmt.Errorf("polynomial degree must be non-negative")
+	errPolynomialNoCoefs    = fmt.Errorf("polynomial must have at least one coefficient")
+	errPolynomialNilCoef    = fmt.Errorf("polynomial coefficient cannot be nil")
+	errSecretOutOfRange     = fmt.Errorf("secret must be in range [0, modulus)")
+	errInsufficientShares   = fmt.Errorf("not enough shares to reconstruct the secret")
+	errDuplicateShare       = fmt.Errorf("duplicate share")
+	errInvalidShare         = fmt.Errorf("invalid share")
+	errSharingTooFewParties = fmt.Errorf("cannot share to fewer parties than degree+1")
+)
+
+// NewPolynomial creates a polynomial over Z/qZ from its coefficients, constant term first.
+// Coefficients are reduced modulo q.
+func NewPolynomial(q *big.Int, coefficients []*big.Int) (*Polynomial, error) {
+	if q == nil || q.Cmp(one) <= 0 {
+		return nil, errInvalidModulus
+	}
+
+	if len(coefficients) == 0 {
+		return nil, errPolynomialNoCoefs
+	}
+
+	coefs := make([]*big.Int, len(coefficients))
+	for i, c := range coefficients {
+		if c == nil {
+			return nil, errPolynomialNilCoef
+		}
+
+		coefs[i] = new(big.Int).Mod(c, q)
+	}
+
+	return &Polynomial{q: new(big.Int).Set(q), coefficients: coefs}, nil
+}
+
+// NewRandomPolynomial samples a polynomial of the given degree over Z/qZ whose constant term is secret.
+// The leading coefficient is non-zero, so the degree is exact.
+func NewRandomPolynomial(rand io.Reader, q *big.Int, degree int, secret *big.Int) (*Polynomial, error) {
+	if q == nil || q.Cmp(one) <= 0 {
+		return nil, errInvalidModulus
+	}
+
+	if degree < 0 {
+		return nil, errPolynomialDegree
+	}
+
+	if secret == nil || !IsInInterval(secret, q) {
+		return nil, errSecretOutOfRange
+	}
+
+	coefs := make([]*big.Int, degree+1)
+	coefs[0] = new(big.Int).Set(secret)
+	for i := 1; i <= degree; i++ {
+		coefs[i] = GetRandomPositiveInt(rand, q)
+		for i == degree && coefs[i].Sign() == 0 {
+			coefs[i] = GetRandomPositiveInt(rand, q)
+		}
+	}
+
+	return NewPolynomial(q, coefs)
+}
+
+// Degree returns the number of coefficients minus one.
+func (p *Polynomial) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+// Secret returns the constant term of the polynomial.
+func (p *Polynomial) Secret() *big.Int {
+	return new(big.Int).Set(p.coefficients[0])
+}
+
+// Evaluate computes p(x) modulo q using Horner's rule.
+func (p *Polynomial) Evaluate(x *big.Int) *big.Int {
+	mod := ModInt(p.q)
+	res := new(big.Int).Set(p.coefficients[len(p.coefficients)-1])
+	for i := len(p.coefficients) - 2; i >= 0; i-- {
+		res = mod.Add(mod.Mul(res, x), p.coefficients[i])
+	}
+
+	return res
+}
+
+// Share evaluates the polynomial at the x-coordinate of each party, as given by XCoordinatesFromIDs.
+func (p *Polynomial) Share(parties SortedPartyIDs) ([]*SecretShare, error) {
+	if len(parties) < len(p.coefficients) {
+		return nil, fmt.Errorf("%w: degree %d, %d parties", errSharingTooFewParties, p.Degree(), len(parties))
+	}
+
+	xs, err := XCoordinatesFromIDs(parties, p.q)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]*SecretShare, len(parties))
+	for i, pid := range parties {
+		shares[i] = &SecretShare{ID: pid, X: xs[i], Value: p.Evaluate(xs[i])}
+	}
+
+	return shares, nil
+}
+
+// ShareSecret splits secret among the members of the committee such that any
+// Threshold()+1 of them can reconstruct it.
+func ShareSecret(rand io.Reader, q, secret *big.Int, committee *Committee) ([]*SecretShare, error) {
+	poly, err := NewRandomPolynomial(rand, q, committee.Threshold(), secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return poly.Share(committee.Parties())
+}
+
+// Reconstruct recovers the secret shared with a polynomial of degree threshold
+// from at least threshold+1 shares using Lagrange interpolation at zero.
+// Only the first threshold+1 shares are used.
+func Reconstruct(q *big.Int, threshold int, shares []*SecretShare) (*big.Int, error) {
+	if threshold < 0 {
+		return nil, errPolynomialDegree
+	}
+
+	if len(shares) < threshold+1 {
+		return nil, fmt.Errorf("%w: got %d, need %d", errInsufficientShares, len(shares), threshold+1)
+	}
+
+	seenIDs := make(map[string]struct{}, len(shares))
+	xs := make([]*big.Int, 0, len(shares))
+	for i, share := range shares {
+		if share == nil || share.X == nil || share.Value == nil {
+			return nil, fmt.Errorf("%w: index %d", errInvalidShare, i)
+		}
+
+		if share.ID != nil {
+			if _, ok := seenIDs[share.ID.ID]; ok {
+				return nil, fmt.Errorf("%w: party %s", errDuplicateShare, share.ID.ID)
+			}
+
+			seenIDs[share.ID.ID] = struct{}{}
+		}
+
+		xs = append(xs, share.X)
+	}
+
+	if err := ValidateXCoordinates(xs, q); err != nil {
+		if errors.Is(err, errXCoordinateDuplicate) {
+			return nil, fmt.Errorf("%w: %w", errDuplicateShare, err)
+		}
+
+		return nil, err
+	}
+
+	xs = xs[:threshold+1]
+	mod := ModInt(q)
+	secret := big.NewInt(0)
+	for i := range xs {
+		coef, err := LagrangeCoefficientAtZero(q, xs, i)
+		if err != nil {
+			return nil, err
+		}
+
+		secret = mod.Add(secret, mod.Mul(coef, shares[i].Value))
+	}
+
+	return secret, nil
+}
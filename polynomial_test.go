@@ -0,0 +1,67 @@
+package common
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPolynomial_Evaluate(t *testing.T) {
+	// f(x) = 1 + 2x + 3x^2 mod 11
+	p, err := NewPolynomial(big.NewInt(11), []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// f(4) = 1 + 8 + 48 = 57 = 2 mod 11
+	if got := p.Evaluate(big.NewInt(4)); got.Int64() != 2 {
+		t.Fatalf("Evaluate(4) = %v, want 2", got)
+	}
+
+	if p.Degree() != 2 || p.Secret().Int64() != 1 {
+		t.Fatalf("unexpected degree %d or secret %v", p.Degree(), p.Secret())
+	}
+}
+
+func TestShareSecret_Reconstruct(t *testing.T) {
+	committee, err := NewCommittee(partyIDs("a", "b", "c", "d", "e"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := GetRandomPositiveInt(rand.Reader, testOrder)
+	shares, err := ShareSecret(rand.Reader, testOrder, secret, committee)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		picked := make([]*SecretShare, len(subset))
+		for i, idx := range subset {
+			picked[i] = shares[idx]
+		}
+
+		got, err := Reconstruct(testOrder, committee.Threshold(), picked)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Cmp(secret) != 0 {
+			t.Fatalf("Reconstruct(%v) = %v, want %v", subset, got, secret)
+		}
+	}
+
+	if _, err := Reconstruct(testOrder, 2, shares[:2]); !errors.Is(err, errInsufficientShares) {
+		t.Fatalf("expected errInsufficientShares, got %v", err)
+	}
+
+	if _, err := Reconstruct(testOrder, 2, []*SecretShare{shares[0], shares[1], shares[0]}); !errors.Is(err, errDuplicateShare) {
+		t.Fatalf("expected errDuplicateShare, got %v", err)
+	}
+
+	anonymous := []*SecretShare{{X: shares[0].X, Value: shares[0].Value}, {X: shares[1].X, Value: shares[1].Value}, {X: shares[0].X, Value: shares[2].Value}}
+	if _, err := Reconstruct(testOrder, 2, anonymous); !errors.Is(err, errDuplicateShare) {
+		t.Fatalf("expected errDuplicateShare for repeated x-coordinates, got %v", err)
+	}
+}
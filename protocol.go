@@ -0,0 +1,149 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// maxProtocolID is the largest protocol identifier that fits in the protocol
+// part of a TrackingID string (two decimal characters).
+const maxProtocolID = 99
+
+// ProtocolInfo describes a protocol known to tss-common.
+type ProtocolInfo struct {
+	// Name is the string identifier carried in MessageWrapper.Protocol.
+	Name ProtocolType
+	// ID is the stable integer identifier carried in TrackingID.Protocol.
+	ID int
+	// Rounds is the number of communication rounds of the protocol, 0 if not declared.
+	Rounds int
+	// TwoCommittees is set for protocols that run between an old and a new committee,
+	// the only ones allowed to route messages with IsToOldCommittee or IsToOldAndNewCommittees.
+	TwoCommittees bool
+}
+
+var (
+	errProtocolNameEmpty    = fmt.Errorf("protocol name cannot be empty")
+	errProtocolIDOutOfRange = fmt.Errorf("protocol id must be in range [1, %d]", maxProtocolID)
+	errProtocolNameTaken    = fmt.Errorf("protocol name already registered")
+	errProtocolIDTaken      = fmt.Errorf("protocol id already registered")
+	errProtocolRounds       = fmt.Errorf("protocol rounds cannot be negative")
+)
+
+type protocolRegistry struct {
+	mtx    sync.RWMutex
+	byName map[ProtocolType]ProtocolInfo
+	byID   map[int]ProtocolInfo
+}
+
+var protocols = &protocolRegistry{
+	byName: map[ProtocolType]ProtocolInfo{},
+	byID:   map[int]ProtocolInfo{},
+}
+
+func init() {
+	for _, info := range []ProtocolInfo{
+		{Name: ProtocolFROSTSign, ID: protocolTypeFROSTSign},
+		{Name: ProtocolFROSTDKG, ID: protocolTypeFROSTDKG},
+		{Name: ProtocolECDSASign, ID: protocolTypeECDSASign},
+		{Name: ProtocolECDSADKG, ID: protocolTypeECDSADKG},
+		{Name: ProtocolECDSAReshare, ID: protocolTypeECDSAReshare, TwoCommittees: true},
+		{Name: ProtocolFROSTReshare, ID: protocolTypeFROSTReshare, TwoCommittees: true},
+		{Name: ProtocolECDSAPresign, ID: protocolTypeECDSAPresign},
+		{Name: ProtocolECDSARefresh, ID: protocolTypeECDSARefresh},
+	} {
+		MustRegisterProtocol(info)
+	}
+}
+
+// RegisterProtocol adds a protocol to the registry consulted by ProtocolType.ToInt,
+// TrackingID.GetProtocolType, TrackingID.FromString and ParseWireMessage.
+// Returns an error if the name or the ID is already in use.
+func RegisterProtocol(info ProtocolInfo) error {
+	if info.Name == "" {
+		return errProtocolNameEmpty
+	}
+
+	if info.ID <= protocolTypeMin || info.ID > maxProtocolID {
+		return errProtocolIDOutOfRange
+	}
+
+	if info.Rounds < 0 {
+		return errProtocolRounds
+	}
+
+	protocols.mtx.Lock()
+	defer protocols.mtx.Unlock()
+
+	if existing, ok := protocols.byName[info.Name]; ok {
+		return fmt.Errorf("%w: %s (id %d)", errProtocolNameTaken, existing.Name, existing.ID)
+	}
+
+	if existing, ok := protocols.byID[info.ID]; ok {
+		return fmt.Errorf("%w: %d (%s)", errProtocolIDTaken, existing.ID, existing.Name)
+	}
+
+	protocols.byName[info.Name] = info
+	protocols.byID[info.ID] = info
+
+	return nil
+}
+
+// MustRegisterProtocol is like RegisterProtocol but panics on error.
+// Intended to be called from init functions.
+func MustRegisterProtocol(info ProtocolInfo) {
+	if err := RegisterProtocol(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupProtocol returns the registered ProtocolInfo for the given name.
+func LookupProtocol(p ProtocolType) (ProtocolInfo, bool) {
+	protocols.mtx.RLock()
+	defer protocols.mtx.RUnlock()
+
+	info, ok := protocols.byName[p]
+	return info, ok
+}
+
+// LookupProtocolByID returns the registered ProtocolInfo for the given integer ID.
+func LookupProtocolByID(id int) (ProtocolInfo, bool) {
+	protocols.mtx.RLock()
+	defer protocols.mtx.RUnlock()
+
+	info, ok := protocols.byID[id]
+	return info, ok
+}
+
+// RegisteredProtocols returns all registered protocols ordered by ID.
+func RegisteredProtocols() []ProtocolInfo {
+	protocols.mtx.RLock()
+	defer protocols.mtx.RUnlock()
+
+	infos := make([]ProtocolInfo, 0, len(protocols.byID))
+	for _, info := range protocols.byID {
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+
+	return infos
+}
+
+// IsRegistered reports whether the protocol type is known to the registry.
+func (p ProtocolType) IsRegistered() bool {
+	_, ok := LookupProtocol(p)
+	return ok
+}
+
+// Info returns the registered ProtocolInfo for p.
+func (p ProtocolType) Info() (ProtocolInfo, bool) {
+	return LookupProtocol(p)
+}
+
+// IsTwoCommittees reports whether p is a registered protocol that involves an old and a new committee.
+func (p ProtocolType) IsTwoCommittees() bool {
+	info, ok := LookupProtocol(p)
+	return ok && info.TwoCommittees
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+// unregisterProtocol removes a protocol registered by a test.
+func unregisterProtocol(name ProtocolType) {
+	protocols.mtx.Lock()
+	defer protocols.mtx.Unlock()
+
+	if info, ok := protocols.byName[name]; ok {
+		delete(protocols.byName, name)
+		delete(protocols.byID, info.ID)
+	}
+}
+
+func TestRegisterProtocol(t *testing.T) {
+	custom := ProtocolInfo{Name: "TEST:REGISTER", ID: 90, Rounds: 3}
+	if err := RegisterProtocol(custom); err != nil {
+		t.Fatalf("RegisterProtocol() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { unregisterProtocol(custom.Name) })
+
+	if got := custom.Name.ToInt(); got != custom.ID {
+		t.Fatalf("ToInt() = %d, want %d", got, custom.ID)
+	}
+
+	got, err := (&TrackingID{Protocol: uint32(custom.ID)}).GetProtocolType()
+	if err != nil || got != custom.Name {
+		t.Fatalf("GetProtocolType() = %q, %v; want %q", got, err, custom.Name)
+	}
+
+	var tid TrackingID
+	if err := tid.FromString("90-0102030000000000000000000000000000000000000000000000000000000000--"); err != nil {
+		t.Fatalf("FromString() with registered protocol: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		info ProtocolInfo
+		err  error
+	}{
+		{"duplicate name", ProtocolInfo{Name: custom.Name, ID: 91}, errProtocolNameTaken},
+		{"duplicate id", ProtocolInfo{Name: "TEST:OTHER", ID: custom.ID}, errProtocolIDTaken},
+		{"builtin id collision", ProtocolInfo{Name: "TEST:OTHER", ID: protocolTypeFROSTSign}, errProtocolIDTaken},
+		{"empty name", ProtocolInfo{ID: 92}, errProtocolNameEmpty},
+		{"reserved id", ProtocolInfo{Name: "TEST:OTHER", ID: protocolTypeMin}, errProtocolIDOutOfRange},
+		{"id too large", ProtocolInfo{Name: "TEST:OTHER", ID: maxProtocolID + 1}, errProtocolIDOutOfRange},
+		{"negative rounds", ProtocolInfo{Name: "TEST:OTHER", ID: 93, Rounds: -1}, errProtocolRounds},
+	}
+
+	for _, tt := range tests {
+		if err := RegisterProtocol(tt.info); !errors.Is(err, tt.err) {
+			t.Errorf("%s: RegisterProtocol() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	if _, ok := LookupProtocolByID(91); ok {
+		t.Fatalf("failed registration must not leave a partial entry")
+	}
+}
+
+func TestUnknownProtocolToInt(t *testing.T) {
+	if got := ProtocolType("UNKNOWN").ToInt(); got != -1 {
+		t.Fatalf("ToInt() = %d, want -1", got)
+	}
+}
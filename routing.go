@@ -0,0 +1,103 @@
+package common
+
+import (
+	"fmt"
+)
+
+var (
+	errRoutingNilMessage       = fmt.Errorf("cannot resolve recipients of a nil message")
+	errRoutingConflictingFlags = fmt.Errorf("message cannot be routed to the old committee and to both committees at once")
+	errRoutingNoOldCommittee   = fmt.Errorf("message is routed to the old committee, but no old committee was given")
+	errRoutingNoNewCommittee   = fmt.Errorf("message is routed to the new committee, but no new committee was given")
+	errRoutingUnknownRecipient = fmt.Errorf("direct recipient is not a member of the target committees")
+	errRoutingToSelf           = fmt.Errorf("direct recipient is the sender")
+)
+
+// RecipientResolver expands MessageRouting into concrete recipients, given the
+// committees a protocol runs with. For protocols with a single committee, set
+// only NewCommittee. OldCommittee is used for resharing.
+type RecipientResolver struct {
+	OldCommittee SortedPartyIDs
+	NewCommittee SortedPartyIDs
+}
+
+// Recipients returns the parties that should receive a message with the given routing.
+// The sender is never part of the result. The result is sorted and deduplicated.
+//
+//   - IsToOldCommittee: targets the old committee.
+//   - IsToOldAndNewCommittees: targets the union of the old and new committees.
+//   - otherwise: targets the new committee.
+//
+// When routing.To is set, the result holds only that party, which must be a member of the targeted committees.
+func (r *RecipientResolver) Recipients(routing *MessageRouting) (SortedPartyIDs, error) {
+	if routing == nil {
+		return nil, errRoutingNilMessage
+	}
+
+	targets, err := r.targetCommittee(routing)
+	if err != nil {
+		return nil, err
+	}
+
+	if !routing.IsBroadcast() {
+		if routing.To.Equals(routing.From) {
+			return nil, fmt.Errorf("%w: %s", errRoutingToSelf, routing.To.ToString())
+		}
+
+		i := targets.Find(routing.To)
+		if i == -1 {
+			return nil, fmt.Errorf("%w: %s", errRoutingUnknownRecipient, routing.To.ToString())
+		}
+
+		return SortedPartyIDs{targets[i]}, nil
+	}
+
+	if routing.From == nil {
+		return targets, nil
+	}
+
+	return targets.Difference(SortedPartyIDs{routing.From}), nil
+}
+
+// MessageRecipients is like Recipients, using the routing of the given Message.
+func (r *RecipientResolver) MessageRecipients(msg Message) (SortedPartyIDs, error) {
+	if msg == nil {
+		return nil, errRoutingNilMessage
+	}
+
+	return r.Recipients(&MessageRouting{
+		From:                    msg.GetFrom(),
+		To:                      msg.GetTo(),
+		IsToOldCommittee:        msg.IsToOldCommittee(),
+		IsToOldAndNewCommittees: msg.IsToOldAndNewCommittees(),
+	})
+}
+
+func (r *RecipientResolver) targetCommittee(routing *MessageRouting) (SortedPartyIDs, error) {
+	switch {
+	case routing.IsToOldCommittee && routing.IsToOldAndNewCommittees:
+		return nil, errRoutingConflictingFlags
+	case routing.IsToOldCommittee:
+		if len(r.OldCommittee) == 0 {
+			return nil, errRoutingNoOldCommittee
+		}
+
+		return r.OldCommittee.Dedup(), nil
+	case routing.IsToOldAndNewCommittees:
+		if len(r.OldCommittee) == 0 {
+			return nil, errRoutingNoOldCommittee
+		}
+
+		if len(r.NewCommittee) == 0 {
+			return nil, errRoutingNoNewCommittee
+		}
+
+		return r.OldCommittee.Union(r.NewCommittee), nil
+	default:
+		if len(r.NewCommittee) == 0 {
+			return nil, errRoutingNoNewCommittee
+		}
+
+		return r.NewCommittee.Dedup(), nil
+	}
+}
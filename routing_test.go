@@ -0,0 +1,69 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecipientResolver_Recipients(t *testing.T) {
+	r := &RecipientResolver{
+		OldCommittee: SortPartyIDs(partyIDs("a", "b", "c")),
+		NewCommittee: SortPartyIDs(partyIDs("c", "d", "e")),
+	}
+
+	tests := []struct {
+		name    string
+		routing *MessageRouting
+		want    []string
+		err     error
+	}{
+		{"broadcast new", &MessageRouting{From: &PartyID{ID: "d"}}, []string{"c", "e"}, nil},
+		{"broadcast old", &MessageRouting{From: &PartyID{ID: "d"}, IsToOldCommittee: true}, []string{"a", "b", "c"}, nil},
+		{"broadcast both", &MessageRouting{From: &PartyID{ID: "c"}, IsToOldAndNewCommittees: true}, []string{"a", "b", "d", "e"}, nil},
+		{"direct new", &MessageRouting{From: &PartyID{ID: "a"}, To: &PartyID{ID: "e"}}, []string{"e"}, nil},
+		{"direct old", &MessageRouting{From: &PartyID{ID: "d"}, To: &PartyID{ID: "a"}, IsToOldCommittee: true}, []string{"a"}, nil},
+		{"direct to wrong committee", &MessageRouting{From: &PartyID{ID: "d"}, To: &PartyID{ID: "a"}}, nil, errRoutingUnknownRecipient},
+		{"direct to outsider", &MessageRouting{From: &PartyID{ID: "d"}, To: &PartyID{ID: "z"}, IsToOldAndNewCommittees: true}, nil, errRoutingUnknownRecipient},
+		{"direct to self", &MessageRouting{From: &PartyID{ID: "d"}, To: &PartyID{ID: "d"}}, nil, errRoutingToSelf},
+		{"conflicting flags", &MessageRouting{IsToOldCommittee: true, IsToOldAndNewCommittees: true}, nil, errRoutingConflictingFlags},
+		{"nil routing", nil, nil, errRoutingNilMessage},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Recipients(tt.routing)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: Recipients() = %v, want %v", tt.name, err, tt.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+
+		assertIDs(t, tt.name, got, tt.want...)
+	}
+
+	if _, err := (&RecipientResolver{NewCommittee: r.NewCommittee}).Recipients(&MessageRouting{IsToOldCommittee: true}); !errors.Is(err, errRoutingNoOldCommittee) {
+		t.Fatalf("expected errRoutingNoOldCommittee, got %v", err)
+	}
+}
+
+func TestRecipientResolver_MessageRecipients(t *testing.T) {
+	r := &RecipientResolver{
+		OldCommittee: SortPartyIDs(partyIDs("a", "b")),
+		NewCommittee: SortPartyIDs(partyIDs("b", "c")),
+	}
+
+	msg := newTestMessage(ProtocolECDSAReshare, MessageRouting{From: &PartyID{ID: "a"}, IsToOldAndNewCommittees: true})
+
+	got, err := r.MessageRecipients(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "MessageRecipients", got, "b", "c")
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// sealingDomain separates the keys and associated data of sealed messages from other uses of X25519 keys.
+const sealingDomain = "tss-common/sealed-message/v1"
+
+// x25519KeySize is the size of X25519 public keys.
+const x25519KeySize = 32
+
+var (
+	errSealNoKey      = fmt.Errorf("recipient has no encryption key")
+	errSealKey        = fmt.Errorf("invalid X25519 encryption key")
+	errSealRequired   = fmt.Errorf("unicast message is not sealed")
+	errSealOpen       = fmt.Errorf("cannot open sealed message")
+	errSealTrackingID = fmt.Errorf("sealed message TrackingID does not match the message it wraps")
+)
+
+// WithEncryptionKey sets the X25519 public key messages to the party are sealed with, and returns the party.
+func (pid *PartyID) WithEncryptionKey(key *ecdh.PublicKey) *PartyID {
+	pid.EncryptionKey = key.Bytes()
+
+	return pid
+}
+
+// HasEncryptionKey reports whether the party has a key to seal messages to it with, see MessageImpl.WireBytes.
+func (pid *PartyID) HasEncryptionKey() bool {
+	return pid != nil && len(pid.EncryptionKey) > 0
+}
+
+// sealWireBytes encrypts the wire bytes to the encryption key of the recipient.
+// Each message is sealed with a fresh ephemeral key, so the AEAD nonce is fixed.
+func sealWireBytes(wireBytes []byte, from, to *PartyID, trackingID *TrackingID) ([]byte, error) {
+	if !to.HasEncryptionKey() {
+		return nil, errSealNoKey
+	}
+
+	recipient, err := ecdh.X25519().NewPublicKey(to.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errSealKey, err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errSealKey, err)
+	}
+
+	sealed := &SealedMessage{
+		EphemeralKey: ephemeral.PublicKey().Bytes(),
+		TrackingId:   trackingID,
+	}
+
+	aead, err := sealingAEAD(shared, sealed.EphemeralKey, recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	sealed.Ciphertext = aead.Seal(nil, nonce, wireBytes, sealed.associatedData(from, to, recipient.Bytes()))
+
+	return proto.Marshal(sealed)
+}
+
+// openWireBytes decrypts the output of sealWireBytes with the private key of the recipient.
+func openWireBytes(sealedBytes []byte, from, to *PartyID, key *ecdh.PrivateKey, opts ParseOptions) ([]byte, *SealedMessage, error) {
+	sealed := new(SealedMessage)
+	if err := opts.unmarshalOptions().Unmarshal(sealedBytes, sealed); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errSealRequired, err)
+	}
+
+	if len(sealed.EphemeralKey) != x25519KeySize {
+		return nil, nil, errSealRequired
+	}
+
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed.EphemeralKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errSealOpen, err)
+	}
+
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errSealOpen, err)
+	}
+
+	recipient := key.PublicKey().Bytes()
+
+	aead, err := sealingAEAD(shared, sealed.EphemeralKey, recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	wireBytes, err := aead.Open(nil, nonce, sealed.Ciphertext, sealed.associatedData(from, to, recipient))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errSealOpen, err)
+	}
+
+	return wireBytes, sealed, nil
+}
+
+// sealingAEAD returns AES-256-GCM keyed by HKDF-SHA256 of the shared secret, salted with both public keys.
+func sealingAEAD(shared, ephemeralKey, recipientKey []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte(nil), ephemeralKey...), recipientKey...)
+
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(shared)
+
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write([]byte(sealingDomain))
+	expand.Write([]byte{1})
+
+	block, err := aes.NewCipher(expand.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// associatedData binds the ciphertext to the sender, the recipient and the TrackingID.
+func (x *SealedMessage) associatedData(from, to *PartyID, recipientKey []byte) []byte {
+	parts := [][]byte{[]byte(from.GetID()), []byte(to.GetID()), recipientKey, x.TrackingId.CanonicalBytes()}
+
+	buf := []byte(sealingDomain)
+	for _, part := range parts {
+		buf = binary.AppendUvarint(buf, uint64(len(part)))
+		buf = append(buf, part...)
+	}
+
+	return buf
+}
+
+// checkTrackingID verifies the TrackingID of the sealed message is the one of the MessageWrapper it wraps.
+func (x *SealedMessage) checkTrackingID(wire *MessageWrapper) error {
+	if !x.TrackingId.Equals(wire.TrackingID) {
+		return fmt.Errorf("%w: %s, wrapped %s", errSealTrackingID, x.TrackingId.ToString(), wire.TrackingID.ToString())
+	}
+
+	return nil
+}
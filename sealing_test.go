@@ -0,0 +1,207 @@
+package common
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func encryptionKey(t *testing.T) *ecdh.PrivateKey {
+	t.Helper()
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestSealWireBytes(t *testing.T) {
+	key := encryptionKey(t)
+	from := NewPartyID("sender", "")
+	to := NewPartyID("receiver", "").WithEncryptionKey(key.PublicKey())
+	trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+	plaintext := []byte("secret share")
+
+	sealed, err := sealWireBytes(plaintext, from, to, trackingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed bytes contain the plaintext")
+	}
+
+	opened, env, err := openWireBytes(sealed, from, to, key, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(opened, plaintext) || !env.TrackingId.Equals(trackingID) {
+		t.Fatal("sealed message does not round trip")
+	}
+
+	t.Run("bound to the sender", func(t *testing.T) {
+		if _, _, err := openWireBytes(sealed, NewPartyID("other", ""), to, key, ParseOptions{}); !errors.Is(err, errSealOpen) {
+			t.Fatalf("expected errSealOpen, got %v", err)
+		}
+	})
+
+	t.Run("bound to the recipient", func(t *testing.T) {
+		if _, _, err := openWireBytes(sealed, from, NewPartyID("other", ""), key, ParseOptions{}); !errors.Is(err, errSealOpen) {
+			t.Fatalf("expected errSealOpen, got %v", err)
+		}
+
+		if _, _, err := openWireBytes(sealed, from, to, encryptionKey(t), ParseOptions{}); !errors.Is(err, errSealOpen) {
+			t.Fatalf("expected errSealOpen, got %v", err)
+		}
+	})
+
+	t.Run("bound to the TrackingID", func(t *testing.T) {
+		env := new(SealedMessage)
+		if err := proto.Unmarshal(sealed, env); err != nil {
+			t.Fatal(err)
+		}
+
+		env.TrackingId = testTrackingID(t, ProtocolECDSASign, 2)
+		tampered, err := proto.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := openWireBytes(tampered, from, to, key, ParseOptions{}); !errors.Is(err, errSealOpen) {
+			t.Fatalf("expected errSealOpen, got %v", err)
+		}
+	})
+
+	t.Run("not sealed", func(t *testing.T) {
+		if _, _, err := openWireBytes(nil, from, to, key, ParseOptions{}); !errors.Is(err, errSealRequired) {
+			t.Fatalf("expected errSealRequired, got %v", err)
+		}
+	})
+
+	if _, err := sealWireBytes(plaintext, from, NewPartyID("receiver", ""), trackingID); !errors.Is(err, errSealNoKey) {
+		t.Fatalf("expected errSealNoKey, got %v", err)
+	}
+
+	if !to.ValidateBasic() || (&PartyID{ID: "receiver", EncryptionKey: make([]byte, 31)}).ValidateBasic() {
+		t.Fatal("expected ValidateBasic to check the size of the encryption key")
+	}
+}
+
+func TestParseWireMessageWithOptions_Sealed(t *testing.T) {
+	key := encryptionKey(t)
+	from := NewPartyID("sender", "")
+	to := NewPartyID("receiver", "").WithEncryptionKey(key.PublicKey())
+	trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+	content := &testContent{SignatureData: &SignatureData{Signature: []byte("secret share")}, protocol: ProtocolECDSASign}
+
+	wireBytes := func(t *testing.T, routing MessageRouting) []byte {
+		t.Helper()
+
+		bz, _, err := NewMessage(routing, content, NewMessageWrapper(routing, content, trackingID)).WireBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return bz
+	}
+
+	opts := ParseOptions{DecryptionKey: key}
+
+	t.Run("unicast is sealed", func(t *testing.T) {
+		bz := wireBytes(t, MessageRouting{From: from, To: to})
+		if bytes.Contains(bz, content.Signature) {
+			t.Fatal("wire bytes contain the content")
+		}
+
+		// opening succeeds: parsing reaches the content, which testContent cannot be unmarshalled into.
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+
+		_, err := ParseWireMessageWithOptions(bz, NewPartyID("other", ""), to, opts)
+		if !errors.Is(err, errSealOpen) {
+			t.Fatalf("expected errSealOpen, got %v", err)
+		}
+
+		var perr *Error
+		if !errors.As(err, &perr) || perr.Culprits()[0].GetID() != "other" {
+			t.Fatalf("expected the sender as culprit, got %v", err)
+		}
+	})
+
+	t.Run("broadcast is not sealed", func(t *testing.T) {
+		bz := wireBytes(t, MessageRouting{From: from})
+		if _, err := ParseWireMessageWithOptions(bz, from, nil, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+
+		// a recipient with an empty ID denotes a broadcast, as in MessageRouting.IsBroadcast.
+		broadcast := (&PartyID{}).WithEncryptionKey(key.PublicKey())
+		bz = wireBytes(t, MessageRouting{From: from, To: broadcast})
+		if !bytes.Contains(bz, content.Signature) {
+			t.Fatal("broadcast wire bytes should not be sealed")
+		}
+
+		if _, err := ParseWireMessageWithOptions(bz, from, broadcast, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+	})
+
+	t.Run("unsealed unicast is rejected", func(t *testing.T) {
+		bz := wireBytes(t, MessageRouting{From: from, To: NewPartyID("receiver", "")})
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errSealRequired) {
+			t.Fatalf("expected errSealRequired, got %v", err)
+		}
+	})
+
+	t.Run("sealing required", func(t *testing.T) {
+		routing := MessageRouting{From: from, To: NewPartyID("receiver", ""), RequireSealing: true}
+		if _, _, err := NewMessage(routing, content, NewMessageWrapper(routing, content, trackingID)).WireBytes(); !errors.Is(err, errSealNoKey) {
+			t.Fatalf("expected errSealNoKey, got %v", err)
+		}
+
+		routing.To = to
+		bz := wireBytes(t, routing)
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+
+		// broadcasts are never sealed.
+		routing.To = nil
+		wireBytes(t, routing)
+	})
+
+	t.Run("TrackingID mismatch", func(t *testing.T) {
+		plain := wireBytes(t, MessageRouting{From: from})
+		bz, err := sealWireBytes(plain, from, to, testTrackingID(t, ProtocolECDSASign, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errSealTrackingID) {
+			t.Fatalf("expected errSealTrackingID, got %v", err)
+		}
+	})
+
+	t.Run("signed and sealed", func(t *testing.T) {
+		signer, signerKey := signingParty(t, "sender", KeyType_KEY_TYPE_ED25519)
+		routing := MessageRouting{From: signer, To: to}
+
+		bz, _, err := SignWireMessage(NewMessage(routing, content, NewMessageWrapper(routing, content, trackingID)), signerKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resolver := PartiesKeyResolver(SortedPartyIDs{signer})
+		if _, err := ParseSignedWireMessage(bz, signer, to, resolver, opts); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errSessionExists     = fmt.Errorf("session already exists")
+	errSessionUnknown    = fmt.Errorf("unknown session")
+	errSessionFinished   = fmt.Errorf("session already finished")
+	errSessionExpired    = fmt.Errorf("session expired")
+	errSessionLimit      = fmt.Errorf("too many concurrent sessions for protocol")
+	errSessionNoTracking = fmt.Errorf("message has no TrackingID")
+	errSessionNilMessage = fmt.Errorf("nil message")
+	errTrackingIDExpired = fmt.Errorf("session TrackingID is already expired")
+)
+
+// SessionConfig configures a SessionRegistry. Zero values disable the corresponding limit.
+type SessionConfig[T any] struct {
+	// Timeout is the lifetime of a session. A TrackingID carrying an earlier Expiry
+	// in its AuxiliaryData expires first.
+	Timeout time.Duration
+	// MaxSessionsPerProtocol bounds the number of concurrent active sessions per protocol.
+	MaxSessionsPerProtocol map[ProtocolType]int
+	// DefaultMaxSessions applies to protocols absent from MaxSessionsPerProtocol.
+	DefaultMaxSessions int
+	// FinishedRetention is how long finished and expired sessions are remembered,
+	// so late messages are reported as such rather than as unknown.
+	FinishedRetention time.Duration
+	// OnExpire is called, without holding the registry lock, for every session that expires before finishing.
+	OnExpire func(trackingID *TrackingID, data T)
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+}
+
+type sessionState int
+
+const (
+	sessionActive sessionState = iota
+	sessionFinished
+	sessionExpired
+)
+
+type sessionEntry[T any] struct {
+	trackingID *TrackingID
+	protocol   ProtocolType
+	data       T
+	state      sessionState
+	expiresAt  time.Time // zero if the session never expires.
+	endedAt    time.Time
+}
+
+// SessionRegistry is a concurrency-safe registry of protocol sessions keyed by TrackingID.
+type SessionRegistry[T any] struct {
+	mtx      sync.Mutex
+	config   SessionConfig[T]
+	sessions map[TrackingIDKey]*sessionEntry[T]
+	active   map[ProtocolType]int
+}
+
+// NewSessionRegistry creates an empty registry.
+func NewSessionRegistry[T any](config SessionConfig[T]) *SessionRegistry[T] {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &SessionRegistry[T]{
+		config:   config,
+		sessions: map[TrackingIDKey]*sessionEntry[T]{},
+		active:   map[ProtocolType]int{},
+	}
+}
+
+func (r *SessionRegistry[T]) maxSessions(protocol ProtocolType) int {
+	if limit, ok := r.config.MaxSessionsPerProtocol[protocol]; ok {
+		return limit
+	}
+
+	return r.config.DefaultMaxSessions
+}
+
+// Create registers a new active session for the TrackingID. When the protocol is at its
+// session limit, its sessions past their deadline are expired first, as by ExpireSessions.
+func (r *SessionRegistry[T]) Create(trackingID *TrackingID, data T) error {
+	if err := trackingID.Validate(); err != nil {
+		return err
+	}
+
+	protocol, err := trackingID.GetProtocolType()
+	if err != nil {
+		return err
+	}
+
+	now := r.config.Now()
+
+	var expiresAt time.Time
+	if r.config.Timeout > 0 {
+		expiresAt = now.Add(r.config.Timeout)
+	}
+
+	expiry, ok, err := trackingID.Expiry()
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if !expiry.After(now) {
+			return errTrackingIDExpired
+		}
+
+		if expiresAt.IsZero() || expiry.Before(expiresAt) {
+			expiresAt = expiry
+		}
+	}
+
+	key := trackingID.Key()
+
+	r.mtx.Lock()
+	expired, err := r.create(key, trackingID, protocol, data, expiresAt, now)
+	r.mtx.Unlock()
+
+	r.notifyExpired(expired)
+
+	return err
+}
+
+// create must be called with the lock held. When the protocol is at its limit, its sessions
+// whose deadline has passed are expired first; they are returned for OnExpire.
+func (r *SessionRegistry[T]) create(key TrackingIDKey, trackingID *TrackingID, protocol ProtocolType, data T, expiresAt, now time.Time) ([]*sessionEntry[T], error) {
+	if _, ok := r.sessions[key]; ok {
+		return nil, fmt.Errorf("%w: %s", errSessionExists, trackingID.ToString())
+	}
+
+	var expired []*sessionEntry[T]
+
+	limit := r.maxSessions(protocol)
+	if limit > 0 && r.active[protocol] >= limit {
+		for _, entry := range r.sessions {
+			if entry.protocol == protocol && entry.state == sessionActive && r.isExpired(entry, now) {
+				r.end(entry, sessionExpired, now)
+				expired = append(expired, entry)
+			}
+		}
+	}
+
+	if limit > 0 && r.active[protocol] >= limit {
+		return expired, fmt.Errorf("%w %s: limit is %d", errSessionLimit, protocol, limit)
+	}
+
+	r.sessions[key] = &sessionEntry[T]{
+		trackingID: proto.Clone(trackingID).(*TrackingID),
+		protocol:   protocol,
+		data:       data,
+		state:      sessionActive,
+		expiresAt:  expiresAt,
+	}
+	r.active[protocol]++
+
+	return expired, nil
+}
+
+// Get returns the data of an active session.
+func (r *SessionRegistry[T]) Get(trackingID *TrackingID) (T, error) {
+	var empty T
+	if trackingID == nil {
+		return empty, errNilTrackID
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	entry, ok := r.sessions[trackingID.Key()]
+	if !ok {
+		return empty, fmt.Errorf("%w: %s", errSessionUnknown, trackingID.ToString())
+	}
+
+	switch {
+	case entry.state == sessionFinished:
+		return empty, fmt.Errorf("%w: %s", errSessionFinished, trackingID.ToString())
+	case entry.state == sessionExpired || r.isExpired(entry, r.config.Now()):
+		return empty, fmt.Errorf("%w: %s", errSessionExpired, trackingID.ToString())
+	}
+
+	return entry.data, nil
+}
+
+// SessionFor returns the data of the active session a message belongs to, identified by
+// its TrackingID. Messages for unknown, finished or expired sessions are rejected.
+func (r *SessionRegistry[T]) SessionFor(msg Message) (T, error) {
+	var empty T
+	if msg == nil || msg.WireMsg() == nil {
+		return empty, errSessionNilMessage
+	}
+
+	trackingID := msg.WireMsg().GetTrackingID()
+	if trackingID == nil {
+		return empty, errSessionNoTracking
+	}
+
+	return r.Get(trackingID)
+}
+
+// Finish marks an active session as finished, freeing its slot. Later messages for it are rejected.
+func (r *SessionRegistry[T]) Finish(trackingID *TrackingID) error {
+	if trackingID == nil {
+		return errNilTrackID
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	entry, ok := r.sessions[trackingID.Key()]
+	if !ok {
+		return fmt.Errorf("%w: %s", errSessionUnknown, trackingID.ToString())
+	}
+
+	switch entry.state {
+	case sessionFinished:
+		return fmt.Errorf("%w: %s", errSessionFinished, trackingID.ToString())
+	case sessionExpired:
+		return fmt.Errorf("%w: %s", errSessionExpired, trackingID.ToString())
+	}
+
+	r.end(entry, sessionFinished, r.config.Now())
+
+	return nil
+}
+
+// ActiveSessions returns the number of active sessions of the protocol.
+func (r *SessionRegistry[T]) ActiveSessions(protocol ProtocolType) int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.active[protocol]
+}
+
+// ExpireSessions expires the active sessions whose deadline has passed, freeing their slot and
+// calling OnExpire for each of them, and forgets sessions that ended more than FinishedRetention ago.
+func (r *SessionRegistry[T]) ExpireSessions() {
+	now := r.config.Now()
+
+	var expired []*sessionEntry[T]
+
+	r.mtx.Lock()
+	for key, entry := range r.sessions {
+		if entry.state == sessionActive && r.isExpired(entry, now) {
+			r.end(entry, sessionExpired, now)
+			expired = append(expired, entry)
+		}
+
+		if entry.state != sessionActive && now.Sub(entry.endedAt) >= r.config.FinishedRetention {
+			delete(r.sessions, key)
+		}
+	}
+	r.mtx.Unlock()
+
+	r.notifyExpired(expired)
+}
+
+// notifyExpired calls OnExpire for the sessions; it must be called without holding the lock.
+func (r *SessionRegistry[T]) notifyExpired(expired []*sessionEntry[T]) {
+	if r.config.OnExpire == nil {
+		return
+	}
+
+	for _, entry := range expired {
+		r.config.OnExpire(entry.trackingID, entry.data)
+	}
+}
+
+// Run calls ExpireSessions every interval until the context is done.
+// It returns immediately if the interval is not positive.
+func (r *SessionRegistry[T]) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.ExpireSessions()
+		}
+	}
+}
+
+func (r *SessionRegistry[T]) isExpired(entry *sessionEntry[T], now time.Time) bool {
+	return !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt)
+}
+
+// end must be called with the lock held.
+func (r *SessionRegistry[T]) end(entry *sessionEntry[T], state sessionState, now time.Time) {
+	entry.state = state
+	entry.endedAt = now
+	r.active[entry.protocol]--
+}
@@ -0,0 +1,194 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func testTrackingID(t *testing.T, protocol ProtocolType, seed byte) *TrackingID {
+	t.Helper()
+
+	tid, err := NewTrackingID(protocol, bytes.Repeat([]byte{seed}, 32), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tid
+}
+
+func TestSessionRegistry_Lifecycle(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	var expired []string
+	reg := NewSessionRegistry(SessionConfig[string]{
+		Timeout:           time.Minute,
+		FinishedRetention: time.Hour,
+		OnExpire:          func(_ *TrackingID, data string) { expired = append(expired, data) },
+		Now:               clock.Now,
+	})
+
+	first := testTrackingID(t, ProtocolECDSASign, 1)
+	second := testTrackingID(t, ProtocolECDSASign, 2)
+
+	if err := reg.Create(first, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Create(second, "second"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Create(first, "again"); !errors.Is(err, errSessionExists) {
+		t.Fatalf("expected errSessionExists, got %v", err)
+	}
+
+	content := &testContent{SignatureData: &SignatureData{}, protocol: ProtocolECDSASign}
+	routing := MessageRouting{From: &PartyID{ID: "a"}}
+	msg := NewMessage(routing, content, NewMessageWrapper(routing, content, first))
+
+	if data, err := reg.SessionFor(msg); err != nil || data != "first" {
+		t.Fatalf("SessionFor() = %q, %v", data, err)
+	}
+
+	if err := reg.Finish(first); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := reg.SessionFor(msg); !errors.Is(err, errSessionFinished) {
+		t.Fatalf("expected errSessionFinished, got %v", err)
+	}
+
+	if _, err := reg.Get(testTrackingID(t, ProtocolECDSASign, 3)); !errors.Is(err, errSessionUnknown) {
+		t.Fatalf("expected errSessionUnknown, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := reg.Get(second); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("expected errSessionExpired, got %v", err)
+	}
+
+	reg.ExpireSessions()
+	if len(expired) != 1 || expired[0] != "second" {
+		t.Fatalf("OnExpire calls = %v, want [second]", expired)
+	}
+
+	if reg.ActiveSessions(ProtocolECDSASign) != 0 {
+		t.Fatalf("expired and finished sessions must free their slot")
+	}
+
+	clock.Advance(time.Hour)
+	reg.ExpireSessions()
+	if _, err := reg.Get(first); !errors.Is(err, errSessionUnknown) {
+		t.Fatalf("sessions should be forgotten after the retention period, got %v", err)
+	}
+}
+
+func TestSessionRegistry_Limits(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	reg := NewSessionRegistry(SessionConfig[int]{
+		MaxSessionsPerProtocol: map[ProtocolType]int{ProtocolFROSTSign: 1},
+		DefaultMaxSessions:     2,
+		Now:                    clock.Now,
+	})
+
+	if err := reg.Create(testTrackingID(t, ProtocolFROSTSign, 1), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Create(testTrackingID(t, ProtocolFROSTSign, 2), 2); !errors.Is(err, errSessionLimit) {
+		t.Fatalf("expected errSessionLimit, got %v", err)
+	}
+
+	for i := byte(1); i <= 2; i++ {
+		if err := reg.Create(testTrackingID(t, ProtocolECDSASign, i), int(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := reg.Create(testTrackingID(t, ProtocolECDSASign, 3), 3); !errors.Is(err, errSessionLimit) {
+		t.Fatalf("expected errSessionLimit, got %v", err)
+	}
+
+	// an expiry in the AuxiliaryData bounds the session lifetime.
+	withExpiry := testTrackingID(t, ProtocolECDSADKG, 1)
+	if err := withExpiry.SetExpiry(clock.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Create(withExpiry, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := reg.Get(withExpiry); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("expected errSessionExpired, got %v", err)
+	}
+
+	if err := reg.Create(testTrackingID(t, ProtocolECDSADKG, 1), 0); err != nil {
+		t.Fatalf("TrackingIDs differing by expiry are distinct sessions: %v", err)
+	}
+}
+
+func TestSessionRegistry_CreateReclaimsExpired(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	var expired []int
+	reg := NewSessionRegistry(SessionConfig[int]{
+		Timeout:            time.Minute,
+		DefaultMaxSessions: 1,
+		OnExpire:           func(_ *TrackingID, data int) { expired = append(expired, data) },
+		Now:                clock.Now,
+	})
+
+	if err := reg.Create(testTrackingID(t, ProtocolECDSASign, 1), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Create(testTrackingID(t, ProtocolECDSASign, 2), 2); !errors.Is(err, errSessionLimit) {
+		t.Fatalf("expected errSessionLimit, got %v", err)
+	}
+
+	// without ExpireSessions, the expired session must not hold its slot.
+	clock.Advance(time.Minute)
+	if err := reg.Create(testTrackingID(t, ProtocolECDSASign, 2), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(expired) != 1 || expired[0] != 1 {
+		t.Fatalf("expected OnExpire for session 1, got %v", expired)
+	}
+
+	if _, err := reg.Get(testTrackingID(t, ProtocolECDSASign, 1)); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("expected errSessionExpired, got %v", err)
+	}
+}
+
+func TestSessionRegistry_RunNonPositiveInterval(t *testing.T) {
+	reg := NewSessionRegistry(SessionConfig[int]{})
+
+	// must return rather than panic.
+	reg.Run(context.Background(), 0)
+}
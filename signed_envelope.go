@@ -0,0 +1,178 @@
+package common
+
+import (
+	"crypto"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// signedEnvelopeDomain separates envelope signatures from other uses of the parties' keys.
+const signedEnvelopeDomain = "tss-common/signed-envelope/v1"
+
+// KeyResolver returns the PartyID, holding its public key, of the sender of a message.
+type KeyResolver func(from *PartyID) (*PartyID, error)
+
+var (
+	errEnvelopeNoSender  = fmt.Errorf("signed envelope requires the sender")
+	errEnvelopeSigner    = fmt.Errorf("signer does not match the public key of the sender")
+	errEnvelopeResolve   = fmt.Errorf("cannot resolve the public key of the sender")
+	errEnvelopeRouting   = fmt.Errorf("signed envelope is addressed from or to another party")
+	errEnvelopeSignature = fmt.Errorf("invalid signed envelope signature")
+	errEnvelopeMismatch  = fmt.Errorf("signed envelope does not match the message it wraps")
+)
+
+// PartiesKeyResolver resolves the keys of the given parties, e.g. a committee built with public keys.
+func PartiesKeyResolver(parties SortedPartyIDs) KeyResolver {
+	return func(from *PartyID) (*PartyID, error) {
+		i := parties.Find(from)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown party %s", from.DisplayName())
+		}
+
+		return parties[i], nil
+	}
+}
+
+// SignWireMessage returns the WireBytes of the message wrapped in a SignedEnvelope signed by the sender.
+// The signer must hold an Ed25519 or ECDSA P-256 key, matching the public key of the sender if it has one.
+func SignWireMessage(msg Message, signer crypto.Signer) ([]byte, *MessageRouting, error) {
+	if msg.GetFrom() == nil {
+		return nil, nil, errEnvelopeNoSender
+	}
+
+	if msg.GetFrom().HasPublicKey() {
+		pub, err := msg.GetFrom().CryptoPublicKey()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !publicKeysEqual(pub, signer.Public()) {
+			return nil, nil, errEnvelopeSigner
+		}
+	}
+
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env := &SignedEnvelope{
+		Message:    bz,
+		From:       msg.GetFrom().GetID(),
+		To:         msg.GetTo().GetID(),
+		TrackingId: msg.WireMsg().GetTrackingID(),
+		Protocol:   string(msg.GetProtocol()),
+	}
+
+	if env.Signature, err = signBytes(signer, env.signingBytes()); err != nil {
+		return nil, nil, err
+	}
+
+	bz, err = proto.Marshal(env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bz, routing, nil
+}
+
+// ParseSignedWireMessage is like ParseWireMessageWithOptions for the output of SignWireMessage.
+// The signature is checked against the public key the resolver returns for `from` before the
+// wrapped message is parsed. Forged or inconsistent envelopes yield an *Error naming `from` as culprit.
+func ParseSignedWireMessage(envelopeBytes []byte, from, to *PartyID, resolver KeyResolver, opts ParseOptions) (ParsedMessage, error) {
+	if from == nil {
+		return nil, errEnvelopeNoSender
+	}
+
+	if err := checkWireSize(envelopeBytes, from, to, opts); err != nil {
+		return nil, err
+	}
+
+	env := new(SignedEnvelope)
+	if err := opts.unmarshalOptions().Unmarshal(envelopeBytes, env); err != nil {
+		return nil, err
+	}
+
+	if env.From != from.GetID() || env.To != to.GetID() {
+		err := fmt.Errorf("%w: from %q to %q", errEnvelopeRouting, env.From, env.To)
+		return nil, culpritError(&MessageWrapper{}, err, unknownRound, from, to)
+	}
+
+	if err := env.verify(from, to, resolver); err != nil {
+		return nil, err
+	}
+
+	// the sender signed the envelope, it is accountable for its content.
+	// The wrapped message is smaller than the envelope, so it is within MaxWireBytes.
+	wire, err := decodeWireMessage(env.Message, from, to, opts)
+	if err != nil {
+		var perr *Error
+		if errors.As(err, &perr) {
+			return nil, err
+		}
+
+		return nil, culpritError(&MessageWrapper{Protocol: env.Protocol}, err, unknownRound, from, to)
+	}
+
+	if err := env.matches(wire); err != nil {
+		return nil, culpritError(wire, err, unknownRound, from, to)
+	}
+
+	return parseWrappedMessage(wire, from, to, opts)
+}
+
+// signingBytes returns the domain-separated, length-prefixed fields covered by the signature.
+func (x *SignedEnvelope) signingBytes() []byte {
+	parts := [][]byte{x.Message, []byte(x.From), []byte(x.To), x.TrackingId.CanonicalBytes(), []byte(x.Protocol)}
+
+	buf := []byte(signedEnvelopeDomain)
+	for _, part := range parts {
+		buf = binary.AppendUvarint(buf, uint64(len(part)))
+		buf = append(buf, part...)
+	}
+
+	return buf
+}
+
+func (x *SignedEnvelope) verify(from, to *PartyID, resolver KeyResolver) error {
+	sender, err := resolver(from)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errEnvelopeResolve, err)
+	}
+
+	if !sender.Equals(from) {
+		return fmt.Errorf("%w: resolved %s", errEnvelopeResolve, sender.DisplayName())
+	}
+
+	pub, err := sender.CryptoPublicKey()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errEnvelopeResolve, err)
+	}
+
+	if !verifyBytes(pub, x.signingBytes(), x.Signature) {
+		return culpritError(&MessageWrapper{}, errEnvelopeSignature, unknownRound, from, to)
+	}
+
+	return nil
+}
+
+// matches checks the envelope agrees with the MessageWrapper it wraps.
+func (x *SignedEnvelope) matches(wire *MessageWrapper) error {
+	if x.Protocol != wire.Protocol {
+		return fmt.Errorf("%w: protocol %s, wrapped %s", errEnvelopeMismatch, x.Protocol, wire.Protocol)
+	}
+
+	if !x.TrackingId.Equals(wire.TrackingID) {
+		return fmt.Errorf("%w: TrackingID %s, wrapped %s", errEnvelopeMismatch, x.TrackingId.ToString(), wire.TrackingID.ToString())
+	}
+
+	return nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	eq, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && eq.Equal(b)
+}
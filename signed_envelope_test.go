@@ -0,0 +1,172 @@
+package common
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// signingParty returns a PartyID holding the public key of a new signer of the given type.
+func signingParty(t *testing.T, id string, keyType KeyType) (*PartyID, crypto.Signer) {
+	t.Helper()
+
+	var signer crypto.Signer
+	switch keyType {
+	case KeyType_KEY_TYPE_ED25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer = key
+	case KeyType_KEY_TYPE_ECDSA_P256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer = key
+	}
+
+	kt, pub, err := EncodePublicKey(signer.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewPartyID(id, id).WithPublicKey(kt, pub), signer
+}
+
+func TestSignedEnvelope(t *testing.T) {
+	for _, keyType := range []KeyType{KeyType_KEY_TYPE_ED25519, KeyType_KEY_TYPE_ECDSA_P256} {
+		t.Run(keyType.String(), func(t *testing.T) {
+			from, signer := signingParty(t, "sender", keyType)
+			to, otherSigner := signingParty(t, "receiver", keyType)
+			resolver := PartiesKeyResolver(SortPartyIDs(UnSortedPartyIDs{from, to}))
+
+			trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+			content := &testContent{SignatureData: &SignatureData{}, protocol: ProtocolECDSASign}
+			routing := MessageRouting{From: from, To: to}
+			msg := NewMessage(routing, content, NewMessageWrapper(routing, content, trackingID))
+
+			bz, _, err := SignWireMessage(msg, signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the signature is valid: parsing reaches the content, which testContent cannot be unmarshalled into.
+			if _, err := ParseSignedWireMessage(bz, from, to, resolver, ParseOptions{}); !errors.Is(err, errParse) {
+				t.Fatalf("expected errParse, got %v", err)
+			}
+
+			if _, _, err := SignWireMessage(msg, otherSigner); !errors.Is(err, errEnvelopeSigner) {
+				t.Fatalf("expected errEnvelopeSigner, got %v", err)
+			}
+
+			assertCulprit := func(t *testing.T, err, expected error) {
+				t.Helper()
+
+				if !errors.Is(err, expected) {
+					t.Fatalf("expected %v, got %v", expected, err)
+				}
+
+				var perr *Error
+				if !errors.As(err, &perr) || len(perr.Culprits()) != 1 || perr.Culprits()[0] != from {
+					t.Fatalf("expected the sender as culprit, got %v", err)
+				}
+			}
+
+			t.Run("tampered", func(t *testing.T) {
+				env := new(SignedEnvelope)
+				if err := proto.Unmarshal(bz, env); err != nil {
+					t.Fatal(err)
+				}
+
+				env.TrackingId = testTrackingID(t, ProtocolECDSASign, 2)
+				tampered, err := proto.Marshal(env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = ParseSignedWireMessage(tampered, from, to, resolver, ParseOptions{})
+				assertCulprit(t, err, errEnvelopeSignature)
+			})
+
+			t.Run("signed by another party", func(t *testing.T) {
+				forged := NewMessage(routing, content, NewMessageWrapper(routing, content, trackingID))
+				forged.(*MessageImpl).From = NewPartyID("sender", "")
+
+				bz, _, err := SignWireMessage(forged, otherSigner)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = ParseSignedWireMessage(bz, from, to, resolver, ParseOptions{})
+				assertCulprit(t, err, errEnvelopeSignature)
+			})
+
+			t.Run("misrouted", func(t *testing.T) {
+				_, err := ParseSignedWireMessage(bz, from, nil, resolver, ParseOptions{})
+				assertCulprit(t, err, errEnvelopeRouting)
+
+				_, err = ParseSignedWireMessage(bz, to, to, resolver, ParseOptions{})
+				if !errors.Is(err, errEnvelopeRouting) {
+					t.Fatalf("expected errEnvelopeRouting, got %v", err)
+				}
+			})
+
+			t.Run("unknown sender", func(t *testing.T) {
+				resolver := PartiesKeyResolver(SortPartyIDs(UnSortedPartyIDs{to}))
+				if _, err := ParseSignedWireMessage(bz, from, to, resolver, ParseOptions{}); !errors.Is(err, errEnvelopeResolve) {
+					t.Fatalf("expected errEnvelopeResolve, got %v", err)
+				}
+			})
+
+			t.Run("too large", func(t *testing.T) {
+				_, err := ParseSignedWireMessage(bz, from, to, resolver, ParseOptions{MaxWireBytes: len(bz) - 1})
+				assertCulprit(t, err, errParseTooLarge)
+			})
+		})
+	}
+}
+
+func TestSignedEnvelope_Matches(t *testing.T) {
+	trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+	wire := &MessageWrapper{Protocol: string(ProtocolECDSASign), TrackingID: trackingID}
+
+	env := &SignedEnvelope{Protocol: string(ProtocolECDSASign), TrackingId: trackingID}
+	if err := env.matches(wire); err != nil {
+		t.Fatal(err)
+	}
+
+	env.Protocol = string(ProtocolFROSTSign)
+	if err := env.matches(wire); !errors.Is(err, errEnvelopeMismatch) {
+		t.Fatalf("expected errEnvelopeMismatch, got %v", err)
+	}
+
+	env.Protocol = string(ProtocolECDSASign)
+	env.TrackingId = nil
+	if err := env.matches(wire); !errors.Is(err, errEnvelopeMismatch) {
+		t.Fatalf("expected errEnvelopeMismatch, got %v", err)
+	}
+}
+
+func TestSignWireMessage_UnsupportedKey(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without a public key on the sender, only the signer's key type is checked.
+	from := NewPartyID("sender", "")
+	content := &testContent{SignatureData: &SignatureData{}, protocol: ProtocolECDSASign}
+	routing := MessageRouting{From: from}
+	msg := NewMessage(routing, content, NewMessageWrapper(routing, content))
+
+	if _, _, err := SignWireMessage(msg, p384); !errors.Is(err, errUnsupportedKey) {
+		t.Fatalf("expected errUnsupportedKey, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var (
+	errSignersThreshold    = fmt.Errorf("threshold must be non-negative and smaller than the number of parties")
+	errSignersNotEnoughOk  = fmt.Errorf("not enough healthy parties to select a signing set")
+	errSignersDuplicateIDs = fmt.Errorf("cannot select signers from a committee with duplicate PartyIDs")
+)
+
+const signerSelectionDomain = "tss-common/signer-selection/v1"
+
+// SelectSigners deterministically picks threshold+1 signers among the healthy parties
+// of a committee. A party at index i of parties is healthy if the bit i of
+// trackingID.PartiesState is set.
+//
+// The selection is a Fisher-Yates shuffle seeded by the hash of the TrackingID's
+// protocol, Digest (and DigestAlgorithm) and PartiesState, so every node holding the same inputs picks the
+// same signers without further coordination. The result is sorted.
+func SelectSigners(parties SortedPartyIDs, threshold int, trackingID *TrackingID) (SortedPartyIDs, error) {
+	if trackingID == nil {
+		return nil, errNilTrackID
+	}
+
+	if threshold < 0 || threshold >= len(parties) {
+		return nil, fmt.Errorf("%w: t=%d, n=%d", errSignersThreshold, threshold, len(parties))
+	}
+
+	if len(parties.Dedup()) != len(parties) {
+		return nil, errSignersDuplicateIDs
+	}
+
+	state := trackingID.PartiesBitset()
+	healthy := make(SortedPartyIDs, 0, len(parties))
+	for i, pid := range parties {
+		if ok, err := state.Test(i); err == nil && ok {
+			healthy = append(healthy, pid)
+		}
+	}
+
+	if len(healthy) < threshold+1 {
+		return nil, fmt.Errorf("%w: got %d, need %d", errSignersNotEnoughOk, len(healthy), threshold+1)
+	}
+
+	rng := newSelectionStream(trackingID)
+	for i := len(healthy) - 1; i > 0; i-- {
+		j := rng.uniform(uint64(i + 1))
+		healthy[i], healthy[j] = healthy[j], healthy[i]
+	}
+
+	signers := healthy[:threshold+1]
+	sort.Sort(signers)
+
+	return signers, nil
+}
+
+// selectionStream is a deterministic stream of uint64 values derived from a seed by hashing a counter.
+type selectionStream struct {
+	seed    [32]byte
+	counter uint64
+}
+
+func newSelectionStream(trackingID *TrackingID) *selectionStream {
+	h := sha256.New()
+	h.Write([]byte(signerSelectionDomain))
+	writeLengthPrefixed := func(b []byte) {
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], trackingID.Protocol)
+	binary.BigEndian.PutUint32(header[4:], uint32(trackingID.DigestAlgorithm))
+	h.Write(header[:])
+	writeLengthPrefixed(trackingID.canonicalDigest())
+	// trailing zeros are insignificant, as in TrackingID.Equals.
+	writeLengthPrefixed(trimTrailingZeros(trackingID.PartiesState))
+
+	s := &selectionStream{}
+	copy(s.seed[:], h.Sum(nil))
+
+	return s
+}
+
+func (s *selectionStream) next() uint64 {
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], s.counter)
+	s.counter++
+
+	out := sha256.Sum256(append(s.seed[:], ctr[:]...))
+
+	return binary.BigEndian.Uint64(out[:8])
+}
+
+// uniform returns a value in [0, n) without modulo bias.
+func (s *selectionStream) uniform(n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		v := s.next()
+		if v < limit {
+			return v % n
+		}
+	}
+}
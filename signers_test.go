@@ -0,0 +1,91 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSelectSigners(t *testing.T) {
+	parties := SortPartyIDs(partyIDs("a", "b", "c", "d", "e", "f", "g"))
+	healthy := []bool{true, true, false, true, true, false, true}
+	tid := &TrackingID{
+		Protocol:     uint32(ProtocolECDSASign.ToInt()),
+		Digest:       bytes.Repeat([]byte{7}, 32),
+		PartiesState: ConvertBoolArrayToByteArray(healthy),
+	}
+
+	signers, err := SelectSigners(parties, 2, tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(signers) != 3 {
+		t.Fatalf("expected 3 signers, got %d", len(signers))
+	}
+
+	for _, pid := range signers {
+		if !healthy[parties.Find(pid)] {
+			t.Fatalf("selected unhealthy party %s", pid.ID)
+		}
+	}
+
+	again, err := SelectSigners(SortPartyIDs(partyIDs("g", "f", "e", "d", "c", "b", "a")), 2, tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "SelectSigners determinism", again, sortedIDs(signers)...)
+
+	// TrackingIDs that are Equals select the same signers.
+	padded := &TrackingID{Protocol: tid.Protocol, Digest: tid.Digest, PartiesState: append(append([]byte{}, tid.PartiesState...), 0, 0)}
+	if !padded.Equals(tid) {
+		t.Fatal("expected the padded TrackingID to equal the original")
+	}
+
+	same, err := SelectSigners(parties, 2, padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "SelectSigners with trailing zeros", same, sortedIDs(signers)...)
+
+	// Changing the digest should eventually lead to a different subset.
+	differs := false
+	for i := byte(0); i < 32 && !differs; i++ {
+		other := &TrackingID{Protocol: tid.Protocol, Digest: bytes.Repeat([]byte{i}, 32), PartiesState: tid.PartiesState}
+		s, err := SelectSigners(parties, 2, other)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		differs = len(s.Difference(signers)) > 0
+	}
+
+	if !differs {
+		t.Fatalf("selection does not depend on the digest")
+	}
+}
+
+func TestSelectSigners_Errors(t *testing.T) {
+	parties := SortPartyIDs(partyIDs("a", "b", "c", "d"))
+	tid := &TrackingID{Digest: []byte{1}, PartiesState: ConvertBoolArrayToByteArray([]bool{true, false, true, false})}
+
+	if _, err := SelectSigners(parties, 2, tid); !errors.Is(err, errSignersNotEnoughOk) {
+		t.Fatalf("expected errSignersNotEnoughOk, got %v", err)
+	}
+
+	if _, err := SelectSigners(parties, 4, tid); !errors.Is(err, errSignersThreshold) {
+		t.Fatalf("expected errSignersThreshold, got %v", err)
+	}
+
+	if _, err := SelectSigners(parties, 1, nil); !errors.Is(err, errNilTrackID) {
+		t.Fatalf("expected errNilTrackID, got %v", err)
+	}
+
+	// a short PartiesState marks the remaining parties as unhealthy instead of panicking.
+	short := &TrackingID{Digest: []byte{1}, PartiesState: nil}
+	if _, err := SelectSigners(parties, 1, short); !errors.Is(err, errSignersNotEnoughOk) {
+		t.Fatalf("expected errSignersNotEnoughOk, got %v", err)
+	}
+}
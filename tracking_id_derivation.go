@@ -0,0 +1,157 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	sessionPathDomain = "tss-common/session-path/v1"
+	// sessionPathSize is the size of the sub-session identifier stored in AuxiliaryData.
+	sessionPathSize = 8
+)
+
+var (
+	errEmptySessionLabel = fmt.Errorf("sub-session label cannot be empty")
+)
+
+// Attempt returns the retry attempt number of the TrackingID, 0 for the first attempt.
+func (t *TrackingID) Attempt() (uint64, error) {
+	if t == nil {
+		return 0, errNilTrackID
+	}
+
+	n, _, err := t.auxUint64(AuxTagAttempt)
+
+	return n, err
+}
+
+// WithAttempt returns a copy of the TrackingID for the given retry attempt of the same request.
+// A nil partiesState keeps the current PartiesState.
+// When the AuxiliaryData outgrows 32 bytes, the result is written in the version 2 string format.
+func (t *TrackingID) WithAttempt(attempt uint64, partiesState []byte) (*TrackingID, error) {
+	if t == nil {
+		return nil, errNilTrackID
+	}
+
+	aux, err := t.AuxData()
+	if err != nil {
+		return nil, err
+	}
+
+	aux.Delete(AuxTagAttempt)
+	if attempt != 0 {
+		if err := aux.SetUint64(AuxTagAttempt, attempt); err != nil {
+			return nil, err
+		}
+	}
+
+	derived := proto.Clone(t).(*TrackingID)
+	derived.SetAuxData(aux)
+	if partiesState != nil {
+		derived.PartiesState = append([]byte{}, partiesState...)
+	}
+
+	return derived, nil
+}
+
+// NextAttempt returns a copy of the TrackingID for the next retry attempt, see WithAttempt.
+func (t *TrackingID) NextAttempt(partiesState []byte) (*TrackingID, error) {
+	attempt, err := t.Attempt()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.WithAttempt(attempt+1, partiesState)
+}
+
+// DeriveChild returns the TrackingID of a sub-session of t running the given protocol
+// (e.g. a presigning session feeding a signature). The child keeps the Digest,
+// PartiesState and payload of t, starts at attempt 0, and is identified by label:
+// deriving the same label from the same parent always yields the same child.
+// Like WithAttempt, the child may need the version 2 string format.
+func (t *TrackingID) DeriveChild(protocol ProtocolType, label string) (*TrackingID, error) {
+	if t == nil {
+		return nil, errNilTrackID
+	}
+
+	if label == "" {
+		return nil, errEmptySessionLabel
+	}
+
+	info, ok := LookupProtocol(protocol)
+	if !ok {
+		return nil, errUnknownProtocolType
+	}
+
+	aux, err := t.AuxData()
+	if err != nil {
+		return nil, err
+	}
+
+	parentPath, _ := aux.Bytes(AuxTagSessionPath)
+
+	h := sha256.New()
+	h.Write([]byte(sessionPathDomain))
+	h.Write([]byte{byte(len(parentPath))})
+	h.Write(parentPath)
+	h.Write([]byte(label))
+
+	aux.Delete(AuxTagAttempt)
+	if err := aux.SetBytes(AuxTagSessionPath, h.Sum(nil)[:sessionPathSize]); err != nil {
+		return nil, err
+	}
+
+	derived := proto.Clone(t).(*TrackingID)
+	derived.Protocol = uint32(info.ID)
+	derived.SetAuxData(aux)
+
+	return derived, nil
+}
+
+// SessionPath returns the identifier of the sub-session, nil for a root request.
+func (t *TrackingID) SessionPath() ([]byte, error) {
+	if t == nil {
+		return nil, errNilTrackID
+	}
+
+	aux, err := t.AuxData()
+	if err != nil {
+		return nil, err
+	}
+
+	path, _ := aux.Bytes(AuxTagSessionPath)
+
+	return path, nil
+}
+
+// SameRoot reports whether both TrackingIDs derive from the same root request, i.e. they
+// only differ by protocol, PartiesState, attempt number or sub-session.
+func (t *TrackingID) SameRoot(other *TrackingID) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+
+	if t.DigestAlgorithm != other.DigestAlgorithm || !bytes.Equal(t.canonicalDigest(), other.canonicalDigest()) {
+		return false
+	}
+
+	return bytes.Equal(t.rootAuxData(), other.rootAuxData())
+}
+
+// rootAuxData returns the AuxiliaryData of the root request of t, without attempt and sub-session.
+// AuxiliaryData that cannot be decoded is returned as-is, so SameRoot stays reflexive.
+func (t *TrackingID) rootAuxData() []byte {
+	aux, err := t.AuxData()
+	if err != nil {
+		return trimTrailingZeros(t.AuxiliaryData)
+	}
+
+	aux.Delete(AuxTagAttempt)
+	aux.Delete(AuxTagSessionPath)
+
+	return trimTrailingZeros(aux.Encode())
+}
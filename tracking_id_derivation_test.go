@@ -0,0 +1,182 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTrackingID_Attempts(t *testing.T) {
+	root := &TrackingID{
+		Protocol:      uint32(ProtocolECDSASign.ToInt()),
+		Digest:        bytes.Repeat([]byte{1}, 32),
+		PartiesState:  []byte{0x0f},
+		AuxiliaryData: []byte("payload"),
+	}
+
+	if n, err := root.Attempt(); err != nil || n != 0 {
+		t.Fatalf("Attempt() = %d, %v; want 0", n, err)
+	}
+
+	retry, err := root.NextAttempt([]byte{0x0e})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := retry.Attempt(); err != nil || n != 1 {
+		t.Fatalf("Attempt() = %d, %v; want 1", n, err)
+	}
+
+	if retry.Equals(root) || !retry.SameRoot(root) {
+		t.Fatalf("a retry must differ from, but share the root of the original request")
+	}
+
+	if !bytes.Equal(retry.PartiesState, []byte{0x0e}) || !bytes.Equal(root.PartiesState, []byte{0x0f}) {
+		t.Fatalf("unexpected PartiesState")
+	}
+
+	again, err := root.WithAttempt(1, []byte{0x0e})
+	if err != nil || !again.Equals(retry) {
+		t.Fatalf("attempt derivation is not deterministic")
+	}
+
+	back, err := retry.WithAttempt(0, []byte{0x0f})
+	if err != nil || !bytes.Equal(back.AuxiliaryData, root.AuxiliaryData) {
+		t.Fatalf("attempt 0 should restore the original AuxiliaryData, got %x", back.AuxiliaryData)
+	}
+
+	// attempts survive the string and canonical encodings.
+	var parsed TrackingID
+	if err := parsed.FromString(retry.ToString()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := parsed.Attempt(); err != nil || n != 1 {
+		t.Fatalf("Attempt() after FromString = %d, %v", n, err)
+	}
+
+	if err := parsed.FromCanonicalBytes(retry.CanonicalBytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := parsed.Attempt(); err != nil || n != 1 {
+		t.Fatalf("Attempt() after FromCanonicalBytes = %d, %v", n, err)
+	}
+}
+
+func TestTrackingID_DeriveChild(t *testing.T) {
+	root := &TrackingID{Protocol: uint32(ProtocolECDSASign.ToInt()), Digest: []byte{1}, PartiesState: []byte{0x07}}
+
+	presign, err := root.DeriveChild(ProtocolECDSAPresign, "presign")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignAgain, _ := root.DeriveChild(ProtocolECDSAPresign, "presign")
+	other, _ := root.DeriveChild(ProtocolECDSAPresign, "other")
+
+	if !presign.Equals(presignAgain) || presign.Equals(other) {
+		t.Fatalf("child derivation must be deterministic and depend on the label")
+	}
+
+	if p, _ := presign.GetProtocolType(); p != ProtocolECDSAPresign {
+		t.Fatalf("child protocol = %s", p)
+	}
+
+	if !presign.SameRoot(root) || !presign.SameRoot(other) {
+		t.Fatalf("children must share the root of their parent")
+	}
+
+	path, err := presign.SessionPath()
+	if err != nil || len(path) != sessionPathSize {
+		t.Fatalf("SessionPath() = %x, %v", path, err)
+	}
+
+	grandChild, _ := presign.DeriveChild(ProtocolECDSASign, "presign")
+	if grandChild.Equals(presign) || !grandChild.SameRoot(root) {
+		t.Fatalf("grandchildren must be distinct and share the root")
+	}
+
+	unrelated := &TrackingID{Protocol: root.Protocol, Digest: []byte{2}, PartiesState: root.PartiesState}
+	if unrelated.SameRoot(root) {
+		t.Fatalf("different digests must not share a root")
+	}
+
+	if _, err := root.DeriveChild(ProtocolECDSAPresign, ""); !errors.Is(err, errEmptySessionLabel) {
+		t.Fatalf("expected errEmptySessionLabel, got %v", err)
+	}
+
+	if _, err := root.DeriveChild("UNKNOWN", "x"); !errors.Is(err, errUnknownProtocolType) {
+		t.Fatalf("expected errUnknownProtocolType, got %v", err)
+	}
+}
+
+func TestTrackingID_DeriveLegacyMagicPayload(t *testing.T) {
+	payload := bytes.Repeat([]byte{0x11}, 32)
+	payload[0] = auxDataMagic
+
+	tid := &TrackingID{Protocol: uint32(ProtocolECDSASign.ToInt()), Digest: bytes.Repeat([]byte{1}, 32), AuxiliaryData: payload}
+
+	if !tid.SameRoot(tid) {
+		t.Fatal("SameRoot must be reflexive")
+	}
+
+	if n, err := tid.Attempt(); err != nil || n != 0 {
+		t.Fatalf("Attempt() = %d, %v; want 0", n, err)
+	}
+
+	// a full 32-byte payload leaves no room for the attempt in the version 1 string format.
+	full, err := tid.NextAttempt(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := full.ToString(); !strings.HasPrefix(s, trackingIDVersion2) {
+		t.Fatalf("retry must move to the version 2 string format, got %s", s)
+	}
+
+	var parsed TrackingID
+	if err := parsed.FromString(full.ToString()); err != nil || !parsed.Equals(full) {
+		t.Fatalf("retry does not round trip through its string: %v", err)
+	}
+
+	if n, err := parsed.Attempt(); err != nil || n != 1 || !parsed.SameRoot(tid) {
+		t.Fatalf("Attempt() = %d, %v; want 1 of the same root", n, err)
+	}
+
+	child, err := full.DeriveChild(ProtocolECDSASign, "presign")
+	if err != nil || !child.SameRoot(tid) {
+		t.Fatalf("DeriveChild() = %v, %v; want a child of the same root", child, err)
+	}
+
+	short := &TrackingID{Protocol: tid.Protocol, Digest: tid.Digest, AuxiliaryData: payload[:16]}
+
+	retry, err := short.NextAttempt(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := retry.Attempt(); err != nil || n != 1 {
+		t.Fatalf("Attempt() = %d, %v; want 1", n, err)
+	}
+
+	if !retry.SameRoot(short) || retry.SameRoot(tid) {
+		t.Fatal("the retry must share the root of its own request only")
+	}
+
+	if s := retry.ToString(); strings.HasPrefix(s, trackingIDVersion2) {
+		t.Fatalf("retry must keep the version 1 string format, got %s", s)
+	}
+
+	back, err := retry.WithAttempt(0, nil)
+	if err != nil || !bytes.Equal(back.AuxiliaryData, short.AuxiliaryData) {
+		t.Fatalf("attempt 0 should restore the legacy payload, got %x, %v", back.AuxiliaryData, err)
+	}
+
+	// structured data that fails validation is still compared as-is.
+	invalid := &TrackingID{Protocol: tid.Protocol, Digest: tid.Digest, AuxiliaryData: []byte{auxDataMagic, auxDataVersion, 0x7f, 0, auxDataEnd}}
+	if !invalid.SameRoot(invalid) {
+		t.Fatal("SameRoot must be reflexive for invalid AuxiliaryData")
+	}
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// trackingIDCanonicalVersion is the first byte of the canonical binary encoding of
+// TrackingIDs with a legacy digest, trackingIDCanonicalVersion2 of those with a DigestAlgorithm.
+const (
+	trackingIDCanonicalVersion  byte = 1
+	trackingIDCanonicalVersion2 byte = 2
+)
+
+var (
+	errCanonicalEmpty        = fmt.Errorf("canonical TrackingID encoding cannot be empty")
+	errCanonicalVersion      = fmt.Errorf("unsupported canonical TrackingID encoding version")
+	errCanonicalTruncated    = fmt.Errorf("canonical TrackingID encoding is truncated")
+	errCanonicalTrailingData = fmt.Errorf("canonical TrackingID encoding has trailing data")
+	errNotCanonical          = fmt.Errorf("TrackingID encoding is not canonical")
+)
+
+// TrackingIDKey is a fixed-size digest of a TrackingID suitable as a map key, see TrackingID.Key.
+type TrackingIDKey [sha256.Size]byte
+
+func (k TrackingIDKey) String() string {
+	return hex.EncodeToString(k[:])
+}
+
+// CanonicalBytes returns the canonical binary encoding of the TrackingID:
+//
+//	version (1 byte) | protocol (uvarint) | digest (32 bytes, zero padded) |
+//	len(parties state) (uvarint) | parties state | len(auxiliary data) (uvarint) | auxiliary data
+//
+// If the DigestAlgorithm is set, version 2 records it and the exact digest instead:
+//
+//	version (1 byte) | protocol (uvarint) | digest algorithm (uvarint) | len(digest) (uvarint) | digest | ...
+//
+// Trailing zero bytes of PartiesState and AuxiliaryData are dropped, so two
+// TrackingIDs have the same encoding if and only if they are Equal.
+// Returns nil for a nil TrackingID.
+func (t *TrackingID) CanonicalBytes() []byte {
+	if t == nil {
+		return nil
+	}
+
+	digest := t.canonicalDigest()
+	parties := trimTrailingZeros(t.PartiesState)
+	aux := trimTrailingZeros(t.AuxiliaryData)
+
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen32+len(digest)+3*binary.MaxVarintLen64+len(parties)+len(aux))
+	if t.DigestAlgorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+		buf = append(buf, trackingIDCanonicalVersion)
+		buf = binary.AppendUvarint(buf, uint64(t.Protocol))
+	} else {
+		buf = append(buf, trackingIDCanonicalVersion2)
+		buf = binary.AppendUvarint(buf, uint64(t.Protocol))
+		buf = binary.AppendUvarint(buf, uint64(t.DigestAlgorithm))
+		buf = binary.AppendUvarint(buf, uint64(len(digest)))
+	}
+
+	buf = append(buf, digest...)
+	buf = binary.AppendUvarint(buf, uint64(len(parties)))
+	buf = append(buf, parties...)
+	buf = binary.AppendUvarint(buf, uint64(len(aux)))
+	buf = append(buf, aux...)
+
+	return buf
+}
+
+// FromCanonicalBytes decodes the output of CanonicalBytes into t.
+// Non-canonical encodings (e.g. with trailing zeros in a part) are rejected.
+func (t *TrackingID) FromCanonicalBytes(b []byte) error {
+	if t == nil {
+		return errNilTrackID
+	}
+
+	if len(b) == 0 {
+		return errCanonicalEmpty
+	}
+
+	if b[0] != trackingIDCanonicalVersion && b[0] != trackingIDCanonicalVersion2 {
+		return fmt.Errorf("%w: %d", errCanonicalVersion, b[0])
+	}
+
+	r := bytes.NewReader(b[1:])
+
+	protocol, err := binary.ReadUvarint(r)
+	if err != nil || protocol > uint64(^uint32(0)) {
+		return errCanonicalTruncated
+	}
+
+	algorithm := DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED
+	digestLen := uint64(32)
+	if b[0] == trackingIDCanonicalVersion2 {
+		alg, err := binary.ReadUvarint(r)
+		if err != nil {
+			return errCanonicalTruncated
+		}
+
+		algorithm = DigestAlgorithm(alg)
+		if alg > uint64(^uint32(0)>>1) || algorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+			return errNotCanonical
+		}
+
+		if digestLen, err = binary.ReadUvarint(r); err != nil || digestLen > uint64(r.Len()) {
+			return errCanonicalTruncated
+		}
+	}
+
+	digest := make([]byte, digestLen)
+	if n, _ := r.Read(digest); n != len(digest) {
+		return errCanonicalTruncated
+	}
+
+	readPart := func() ([]byte, error) {
+		l, err := binary.ReadUvarint(r)
+		if err != nil || l > uint64(r.Len()) {
+			return nil, errCanonicalTruncated
+		}
+
+		if l == 0 {
+			return nil, nil
+		}
+
+		part := make([]byte, l)
+		if _, err := r.Read(part); err != nil {
+			return nil, errCanonicalTruncated
+		}
+
+		if part[len(part)-1] == 0 {
+			return nil, errNotCanonical
+		}
+
+		return part, nil
+	}
+
+	parties, err := readPart()
+	if err != nil {
+		return err
+	}
+
+	aux, err := readPart()
+	if err != nil {
+		return err
+	}
+
+	if r.Len() != 0 {
+		return errCanonicalTrailingData
+	}
+
+	t.Protocol = uint32(protocol)
+	t.DigestAlgorithm = algorithm
+	t.Digest = digest
+	t.PartiesState = parties
+	t.AuxiliaryData = aux
+
+	return nil
+}
+
+// Hash returns the SHA-256 of the canonical encoding of the TrackingID.
+func (t *TrackingID) Hash() [sha256.Size]byte {
+	return sha256.Sum256(t.CanonicalBytes())
+}
+
+// Key returns a comparable key for the TrackingID: t.Key() == other.Key() if and only if t.Equals(other).
+func (t *TrackingID) Key() TrackingIDKey {
+	return TrackingIDKey(t.Hash())
+}
+
+func trimTrailingZeros(b []byte) []byte {
+	end := len(b)
+	for end > 0 && b[end-1] == 0 {
+		end--
+	}
+
+	return b[:end]
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestTrackingID_CanonicalRoundTrip(t *testing.T) {
+	tid := &TrackingID{
+		Protocol:      uint32(ProtocolFROSTSign.ToInt()),
+		Digest:        []byte{1, 2, 3},
+		PartiesState:  []byte{0xff, 0x01, 0x00},
+		AuxiliaryData: bytes.Repeat([]byte{7}, 40),
+	}
+
+	var decoded TrackingID
+	if err := decoded.FromCanonicalBytes(tid.CanonicalBytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equals(tid) || decoded.Key() != tid.Key() {
+		t.Fatalf("canonical round-trip mismatch")
+	}
+
+	if !bytes.Equal(decoded.CanonicalBytes(), tid.CanonicalBytes()) {
+		t.Fatalf("re-encoding is not stable")
+	}
+}
+
+func TestTrackingID_CanonicalAgreesWithEquals(t *testing.T) {
+	base := &TrackingID{Protocol: 1, Digest: []byte{1, 2, 3}, PartiesState: []byte{5}, AuxiliaryData: []byte{9}}
+
+	tests := []struct {
+		name  string
+		other *TrackingID
+		equal bool
+	}{
+		{"trailing zeros", &TrackingID{Protocol: 1, Digest: []byte{1, 2, 3, 0}, PartiesState: []byte{5, 0, 0}, AuxiliaryData: []byte{9, 0}}, true},
+		{"different protocol", &TrackingID{Protocol: 2, Digest: base.Digest, PartiesState: base.PartiesState, AuxiliaryData: base.AuxiliaryData}, false},
+		{"parties moved to aux", &TrackingID{Protocol: 1, Digest: base.Digest, PartiesState: nil, AuxiliaryData: []byte{5, 9}}, false},
+		{"different digest", &TrackingID{Protocol: 1, Digest: []byte{1, 2, 4}, PartiesState: base.PartiesState, AuxiliaryData: base.AuxiliaryData}, false},
+	}
+
+	for _, tt := range tests {
+		if base.Equals(tt.other) != tt.equal {
+			t.Errorf("%s: Equals() = %v, want %v", tt.name, !tt.equal, tt.equal)
+		}
+
+		if bytes.Equal(base.CanonicalBytes(), tt.other.CanonicalBytes()) != tt.equal {
+			t.Errorf("%s: canonical encoding disagrees with Equals", tt.name)
+		}
+
+		if (base.Key() == tt.other.Key()) != tt.equal {
+			t.Errorf("%s: Key disagrees with Equals", tt.name)
+		}
+	}
+
+	// keys are usable in maps
+	m := map[TrackingIDKey]int{base.Key(): 1}
+	if m[tests[0].other.Key()] != 1 {
+		t.Fatalf("equal TrackingIDs should map to the same key")
+	}
+}
+
+func TestTrackingID_FromCanonicalBytes_Errors(t *testing.T) {
+	valid := (&TrackingID{Protocol: 1, Digest: []byte{1}, PartiesState: []byte{1}, AuxiliaryData: []byte{2}}).CanonicalBytes()
+
+	nonCanonical := append([]byte{}, valid[:len(valid)-4]...)
+	nonCanonical = append(nonCanonical, 2, 1, 0, 1, 2) // parties state with a trailing zero
+
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"empty", nil, errCanonicalEmpty},
+		{"version", append([]byte{9}, valid[1:]...), errCanonicalVersion},
+		{"truncated digest", valid[:10], errCanonicalTruncated},
+		{"truncated part", valid[:len(valid)-1], errCanonicalTruncated},
+		{"trailing data", append(append([]byte{}, valid...), 0), errCanonicalTrailingData},
+		{"trailing zero", nonCanonical, errNotCanonical},
+	}
+
+	for _, tt := range tests {
+		var tid TrackingID
+		if err := tid.FromCanonicalBytes(tt.input); !errors.Is(err, tt.err) {
+			t.Errorf("%s: FromCanonicalBytes() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
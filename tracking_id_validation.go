@@ -0,0 +1,92 @@
+package common
+
+import (
+	"fmt"
+)
+
+// NewTrackingID builds a validated TrackingID. The PartiesState holds one bit per
+// member of committee, set for each party in healthy, see NewPartiesState. A nil
+// committee leaves the PartiesState empty.
+func NewTrackingID(protocol ProtocolType, digest []byte, committee SortedPartyIDs, healthy []*PartyID, auxiliaryData []byte) (*TrackingID, error) {
+	return newTrackingID(protocol, DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED, digest, committee, healthy, auxiliaryData)
+}
+
+func newTrackingID(protocol ProtocolType, algorithm DigestAlgorithm, digest []byte, committee SortedPartyIDs, healthy []*PartyID, auxiliaryData []byte) (*TrackingID, error) {
+	info, ok := LookupProtocol(protocol)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownProtocolType, protocol)
+	}
+
+	var partiesState []byte
+	if committee != nil {
+		state, err := NewPartiesState(committee, healthy)
+		if err != nil {
+			return nil, err
+		}
+
+		partiesState = state
+	}
+
+	t := &TrackingID{
+		Protocol:        uint32(info.ID),
+		Digest:          append([]byte(nil), digest...),
+		PartiesState:    partiesState,
+		AuxiliaryData:   append([]byte(nil), auxiliaryData...),
+		DigestAlgorithm: algorithm,
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Validate applies the rules of FromStringWithLimits to the TrackingID, bounding the parts by DefaultTrackingIDLimits,
+// which DefaultParseOptions applies to received messages. FromString does not bound the parts, so IDs beyond these
+// limits round-trip through strings but fail Validate, NewTrackingID and NewMessageWrapperChecked;
+// use ValidateWithLimits for larger committees.
+func (t *TrackingID) Validate() error {
+	return t.ValidateWithLimits(DefaultTrackingIDLimits)
+}
+
+// ValidateWithLimits checks that the TrackingID would be accepted by FromStringWithLimits:
+// the protocol is registered, the Digest length matches its DigestAlgorithm (32 bytes
+// for legacy digests), and the PartiesState and AuxiliaryData are within the limits.
+func (t *TrackingID) ValidateWithLimits(limits TrackingIDLimits) error {
+	if t == nil {
+		return errNilTrackID
+	}
+
+	if !isValidProtocolType(int(t.Protocol)) {
+		return errUnknownProtocolType
+	}
+
+	if err := validateDigest(t.DigestAlgorithm, t.Digest, limits); err != nil {
+		return err
+	}
+
+	return t.validateStateSizes(limits)
+}
+
+// validateSizes bounds every part of the TrackingID, including the Digest whatever its algorithm,
+// without checking the parts are well-formed.
+func (t *TrackingID) validateSizes(limits TrackingIDLimits) error {
+	if limits.MaxDigestBytes > 0 && len(t.Digest) > limits.MaxDigestBytes {
+		return fmt.Errorf("%w: Digest must be at most %d bytes", errTrackidPartTooLong, limits.MaxDigestBytes)
+	}
+
+	return t.validateStateSizes(limits)
+}
+
+func (t *TrackingID) validateStateSizes(limits TrackingIDLimits) error {
+	if limits.MaxPartiesStateBytes > 0 && len(t.PartiesState) > limits.MaxPartiesStateBytes {
+		return fmt.Errorf("%w: PartiesState must be at most %d bytes", errTrackidPartTooLong, limits.MaxPartiesStateBytes)
+	}
+
+	if limits.MaxAuxiliaryDataBytes > 0 && len(t.AuxiliaryData) > limits.MaxAuxiliaryDataBytes {
+		return fmt.Errorf("%w: AuxiliaryData must be at most %d bytes", errTrackidPartTooLong, limits.MaxAuxiliaryDataBytes)
+	}
+
+	return nil
+}
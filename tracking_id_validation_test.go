@@ -0,0 +1,77 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNewTrackingID(t *testing.T) {
+	committee := SortPartyIDs(partyIDs("a", "b", "c"))
+	digest := bytes.Repeat([]byte{1}, 32)
+
+	tid, err := NewTrackingID(ProtocolFROSTSign, digest, committee, partyIDs("a", "c"), []byte{9})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed TrackingID
+	if err := parsed.FromString(tid.ToString()); err != nil || !parsed.Equals(tid) {
+		t.Fatalf("a built TrackingID must survive FromString(ToString()): %v", err)
+	}
+
+	healthy, err := tid.HealthyParties(committee)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertIDs(t, "healthy", healthy, "a", "c")
+
+	tests := []struct {
+		name      string
+		protocol  ProtocolType
+		digest    []byte
+		committee SortedPartyIDs
+		healthy   []*PartyID
+		aux       []byte
+		err       error
+	}{
+		{"unknown protocol", "UNKNOWN", digest, nil, nil, nil, errUnknownProtocolType},
+		{"empty digest", ProtocolFROSTSign, nil, nil, nil, nil, errTrackidMustHaveDigest},
+		{"short digest", ProtocolFROSTSign, []byte{1}, nil, nil, nil, errTrackingIDigestLength},
+		{"outsider", ProtocolFROSTSign, digest, committee, partyIDs("z"), nil, errPartyNotInCommittee},
+		{"oversized aux", ProtocolFROSTSign, digest, nil, nil, make([]byte, DefaultTrackingIDLimits.MaxAuxiliaryDataBytes+1), errTrackidPartTooLong},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewTrackingID(tt.protocol, tt.digest, tt.committee, tt.healthy, tt.aux); !errors.Is(err, tt.err) {
+			t.Errorf("%s: NewTrackingID() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestNewMessageWrapperChecked(t *testing.T) {
+	routing := MessageRouting{From: &PartyID{ID: "a"}}
+	content := &testContent{SignatureData: &SignatureData{}, protocol: ProtocolECDSASign}
+
+	invalid := &TrackingID{Protocol: uint32(ProtocolECDSASign.ToInt()), Digest: []byte{1, 2, 3}}
+	if _, err := NewMessageWrapperChecked(routing, content, invalid); !errors.Is(err, errTrackingIDigestLength) {
+		t.Fatalf("NewMessageWrapperChecked() = %v, want errTrackingIDigestLength", err)
+	}
+
+	// existing callers of NewMessageWrapper can still send short digests, as before.
+	msg := NewMessage(routing, content, NewMessageWrapper(routing, content, invalid))
+	if _, _, err := msg.WireBytes(); err != nil {
+		t.Fatalf("WireBytes() unexpected error %v", err)
+	}
+
+	valid := &TrackingID{Protocol: uint32(ProtocolECDSASign.ToInt()), Digest: bytes.Repeat([]byte{1}, 32)}
+	wire, err := NewMessageWrapperChecked(routing, content, valid)
+	if err != nil || wire.TrackingID != valid {
+		t.Fatalf("NewMessageWrapperChecked() unexpected error %v", err)
+	}
+
+	if _, err := NewMessageWrapperChecked(routing, content); err != nil {
+		t.Fatalf("NewMessageWrapperChecked() without TrackingID unexpected error %v", err)
+	}
+}
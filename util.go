@@ -1,6 +1,7 @@
 package common
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"strconv"
@@ -23,7 +24,7 @@ func (t *TrackingID) BitLen() int {
 	return len(t.PartiesState) * 8
 }
 
-// Will panic if i is out of bounds
+// Will panic if i is out of bounds, see PartyState for a bounds-checked version.
 func (t *TrackingID) PartyStateOk(i int) bool {
 	// Find the index of the byte containing the bit
 	byteIndex := i / 8
@@ -48,16 +49,55 @@ func ConvertByteArrayToBoolArray(byteArray []byte, numBools int) []bool {
 
 const nilTrackID = "nilTrackID"
 
+// trackingIDVersion2 prefixes TrackingID strings whose PartiesState or AuxiliaryData
+// exceed the 32 bytes allowed by the original (version 1) format.
+const trackingIDVersion2 = "v2"
+
+// trackingIDVersion3 prefixes TrackingID strings with a DigestAlgorithm, whose Digest is stored exactly.
+const trackingIDVersion3 = "v3"
+
+// legacyTrackingIDPartBytes is the maximal size of each part in the version 1 string format.
+const legacyTrackingIDPartBytes = 32
+
+// TrackingIDLimits bounds the size of the parts of a TrackingID parsed from a version 2 string.
+// A zero value means no limit.
+type TrackingIDLimits struct {
+	MaxPartiesStateBytes  int
+	MaxAuxiliaryDataBytes int
+	// MaxDigestBytes bounds digests of DIGEST_ALGORITHM_RAW.
+	MaxDigestBytes int
+}
+
+// DefaultTrackingIDLimits allows committees of up to 8192 parties, 1KiB of AuxiliaryData
+// and raw digests of up to 1KiB.
+var DefaultTrackingIDLimits = TrackingIDLimits{
+	MaxPartiesStateBytes:  1024,
+	MaxAuxiliaryDataBytes: 1024,
+	MaxDigestBytes:        1024,
+}
+
 // Creates a byte-string representation of the TrackingID.
 // output is in the format "ProtocolType-Digest-PartiesState-AuxiliaryData".
 // protocolType is an integer corresponding to the ProtocolType enum.
 // Each part of Digest-PartiesState-AuxiliaryData is a hexadecimal representation of the respective byte slice.
+// If PartiesState or AuxiliaryData are longer than 32 bytes, the output is prefixed
+// with the version: "v2-ProtocolType-Digest-PartiesState-AuxiliaryData".
+// If the DigestAlgorithm is set, the output records it and the exact Digest:
+// "v3-ProtocolType-DigestAlgorithm-Digest-PartiesState-AuxiliaryData".
 // If the TrackingID is nil, returns "nilTrackID".
 func (t *TrackingID) ToString() string {
 	if t == nil {
 		return nilTrackID
 	}
 
+	if t.DigestAlgorithm != DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+		return fmt.Sprintf("%s-%d-%d-%x-%x-%x", trackingIDVersion3, t.Protocol, t.DigestAlgorithm, t.Digest, t.PartiesState, t.AuxiliaryData)
+	}
+
+	if len(t.PartiesState) > legacyTrackingIDPartBytes || len(t.AuxiliaryData) > legacyTrackingIDPartBytes {
+		return fmt.Sprintf("%s-%d-%x-%x-%x", trackingIDVersion2, t.Protocol, pad32(t.Digest), t.PartiesState, t.AuxiliaryData)
+	}
+
 	return fmt.Sprintf("%d-%x-%x-%x", t.Protocol, pad32(t.Digest), t.PartiesState, t.AuxiliaryData)
 }
 
@@ -67,12 +107,12 @@ func (x *TrackingID) ToByteString() []byte {
 
 var (
 	errNilTrackID                  = fmt.Errorf("nil TrackingID")
-	errTrackidPartTooLong          = fmt.Errorf("TrackingID part too long, must be at most 64 characters (32 bytes) each")
+	errTrackidPartTooLong          = fmt.Errorf("TrackingID part too long")
 	errTrackidMustHaveDigest       = fmt.Errorf("TrackingID must have a non-empty Digest part")
 	errTrackingIDigestLength       = fmt.Errorf("TrackingID Digest must be exactly 64 hex characters (32 bytes)")
 	errTrackidMustHaveProtocolType = fmt.Errorf("TrackingID must have a non-empty ProtocolType part")
 	errTrackidStringEmpty          = fmt.Errorf("TrackingID string cannot be empty")
-	errTrackidInvalidFormat        = fmt.Errorf("invalid TrackingID format, expected 'ProtocolType-Digest-PartiesState-AuxiliaryData'")
+	errTrackidInvalidFormat        = fmt.Errorf("invalid TrackingID format, expected '[v2-]ProtocolType-Digest-PartiesState-AuxiliaryData' or 'v3-ProtocolType-DigestAlgorithm-Digest-PartiesState-AuxiliaryData'")
 	errUnknownProtocolType         = fmt.Errorf("unknown protocol type in TrackingID")
 )
 
@@ -85,10 +125,22 @@ var (
 // even if the PartiesState or AuxiliaryData are nil.
 // Furthermore, an Empty digest or ProtocolType is not allowed.
 // Expects Digest, ProtocolType, PartiesState, and AuxiliaryData to be in hexadecimal
-// format and have at most 32 bytes worth of data each.
+// format; in unversioned strings, they have at most 32 bytes worth of data each.
+//
+// Strings prefixed with "v2-" may hold PartiesState and AuxiliaryData of any length.
+// Strings prefixed with "v3-" additionally hold a DigestAlgorithm after the ProtocolType,
+// and a Digest whose length matches it.
+// FromString accepts anything ToString emits; use FromStringWithLimits to bound
+// the parts of untrusted strings, e.g. with DefaultTrackingIDLimits. Note that
+// TrackingID.Validate applies DefaultTrackingIDLimits, so it rejects parsed IDs beyond them.
 //
 // example: "1-a1b2c3-d4e5f6-1f", "0-a1b2c3-d4e5f6-", "2-a1b2c3--1f", 0-a1b2c3--
 func (t *TrackingID) FromString(s string) error {
+	return t.FromStringWithLimits(s, TrackingIDLimits{})
+}
+
+// FromStringWithLimits is like FromString, but bounds the parts of version 2 and 3 strings by the given limits.
+func (t *TrackingID) FromStringWithLimits(s string, limits TrackingIDLimits) error {
 	if t == nil {
 		return errNilTrackID
 	}
@@ -103,6 +155,34 @@ func (t *TrackingID) FromString(s string) error {
 
 	// Split the string into parts
 	parts := strings.Split(s, "-")
+
+	// maximal amount of bytes in the Digest, PartiesState and AuxiliaryData parts.
+	maxBytes := [3]int{legacyTrackingIDPartBytes, legacyTrackingIDPartBytes, legacyTrackingIDPartBytes}
+	algorithm := DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED
+	switch parts[0] {
+	case trackingIDVersion2:
+		parts = parts[1:]
+		maxBytes[1] = limits.MaxPartiesStateBytes
+		maxBytes[2] = limits.MaxAuxiliaryDataBytes
+	case trackingIDVersion3:
+		if len(parts) != 6 {
+			return errTrackidInvalidFormat
+		}
+
+		alg, err := strconv.ParseUint(parts[2], 10, 8)
+		if err != nil {
+			return fmt.Errorf("failed to parse TrackingID DigestAlgorithm: %w", err)
+		}
+
+		algorithm = DigestAlgorithm(alg)
+		if algorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED {
+			return errDigestAlgorithm
+		}
+
+		parts = append([]string{parts[1]}, parts[3:]...)
+		maxBytes = [3]int{limits.MaxDigestBytes, limits.MaxPartiesStateBytes, limits.MaxAuxiliaryDataBytes}
+	}
+
 	if len(parts) != 4 {
 		return errTrackidInvalidFormat
 	}
@@ -130,23 +210,22 @@ func (t *TrackingID) FromString(s string) error {
 		return errTrackidMustHaveDigest
 	}
 
-	if len(parts[1]) != 64 {
+	if algorithm == DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED && len(parts[1]) != 64 {
 		return errTrackingIDigestLength
 	}
 
 	t.Digest = nil
 	t.PartiesState = nil
 	t.AuxiliaryData = nil
+	t.DigestAlgorithm = DigestAlgorithm_DIGEST_ALGORITHM_UNSPECIFIED
 
 	byteParts := make([][]byte, 3)
 	for i, hexstring := range parts[1:] {
-		if len(hexstring) > 64 {
-			return errTrackidPartTooLong
+		if maxBytes[i] > 0 && len(hexstring) > 2*maxBytes[i] {
+			return fmt.Errorf("%w: must be at most %d hex characters (%d bytes)", errTrackidPartTooLong, 2*maxBytes[i], maxBytes[i])
 		}
 
 		if len(hexstring) == 0 {
-			byteParts = append(byteParts, nil)
-
 			continue
 		}
 
@@ -158,9 +237,14 @@ func (t *TrackingID) FromString(s string) error {
 		byteParts[i] = tmp
 	}
 
+	if err := validateDigest(algorithm, byteParts[0], limits); err != nil {
+		return err
+	}
+
 	t.Digest = byteParts[0]
 	t.PartiesState = byteParts[1]
 	t.AuxiliaryData = byteParts[2]
+	t.DigestAlgorithm = algorithm
 
 	return nil
 }
@@ -171,6 +255,7 @@ func pad32(b []byte) [32]byte {
 	return padded
 }
 
+// Equals compares the TrackingIDs by their canonical encoding, see CanonicalBytes.
 func (t *TrackingID) Equals(other *TrackingID) bool {
 	if t == nil && other == nil {
 		return true
@@ -180,10 +265,7 @@ func (t *TrackingID) Equals(other *TrackingID) bool {
 		return false
 	}
 
-	return t.Protocol == other.Protocol &&
-		pad32(t.Digest) == pad32(other.Digest) &&
-		pad32(t.PartiesState) == pad32(other.PartiesState) &&
-		pad32(t.AuxiliaryData) == pad32(other.AuxiliaryData)
+	return bytes.Equal(t.CanonicalBytes(), other.CanonicalBytes())
 }
 
 /*
@@ -196,20 +278,10 @@ func (t *TrackingID) GetProtocolType() (ProtocolType, error) {
 		return "", errNilTrackID
 	}
 
-	if !isValidProtocolType(int(t.Protocol)) {
+	info, ok := LookupProtocolByID(int(t.Protocol))
+	if !ok {
 		return "", errUnknownProtocolType
 	}
 
-	switch int(t.Protocol) {
-	case protocolTypeFROSTSign:
-		return ProtocolFROSTSign, nil
-	case protocolTypeFROSTDKG:
-		return ProtocolFROSTDKG, nil
-	case protocolTypeECDSASign:
-		return ProtocolECDSASign, nil
-	case protocolTypeECDSADKG:
-		return ProtocolECDSADKG, nil
-	default:
-		return "", errUnknownProtocolType
-	}
+	return info.Name, nil
 }
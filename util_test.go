@@ -485,7 +485,7 @@ func TestGetProtocolType(t *testing.T) {
 		},
 		{
 			name:    "invalid protocol max (too high)",
-			target:  &TrackingID{Protocol: protocolTypeMax}, // not a valid protocolType
+			target:  &TrackingID{Protocol: protocolTypeECDSARefresh + 1}, // not a valid protocolType
 			want:    "",
 			wantErr: errUnknownProtocolType,
 		},
@@ -513,3 +513,77 @@ func TestGetProtocolType(t *testing.T) {
 		})
 	}
 }
+
+func TestTrackingID_LargeCommitteeRoundTrip(t *testing.T) {
+	bools := make([]bool, 300)
+	for i := range bools {
+		bools[i] = i%3 != 0
+	}
+
+	tid := &TrackingID{
+		Protocol:      uint32(ProtocolECDSASign.ToInt()),
+		Digest:        bytes.Repeat([]byte{0xab}, 32),
+		PartiesState:  ConvertBoolArrayToByteArray(bools),
+		AuxiliaryData: bytes.Repeat([]byte{0xcd}, 100),
+	}
+
+	s := tid.ToString()
+	if !strings.HasPrefix(s, "v2-") {
+		t.Fatalf("expected a v2 string, got %q", s)
+	}
+
+	var parsed TrackingID
+	if err := parsed.FromString(s); err != nil {
+		t.Fatalf("FromString(ToString()) failed: %v", err)
+	}
+
+	if !parsed.Equals(tid) || !bytes.Equal(parsed.PartiesState, tid.PartiesState) || !bytes.Equal(parsed.AuxiliaryData, tid.AuxiliaryData) {
+		t.Fatalf("round-trip mismatch")
+	}
+
+	// longer parts must not be truncated by Equals
+	other := &TrackingID{Protocol: tid.Protocol, Digest: tid.Digest, PartiesState: tid.PartiesState, AuxiliaryData: bytes.Repeat([]byte{0xcd}, 101)}
+	if other.Equals(tid) {
+		t.Fatalf("Equals should detect differences beyond 32 bytes")
+	}
+
+	limits := TrackingIDLimits{MaxPartiesStateBytes: 38, MaxAuxiliaryDataBytes: 64}
+	if err := parsed.FromStringWithLimits(s, limits); !errors.Is(err, errTrackidPartTooLong) {
+		t.Fatalf("expected errTrackidPartTooLong with strict limits, got %v", err)
+	}
+
+	if err := parsed.FromStringWithLimits(s, TrackingIDLimits{}); err != nil {
+		t.Fatalf("zero limits should not bound the parts: %v", err)
+	}
+
+	// FromString accepts whatever ToString emits, beyond DefaultTrackingIDLimits.
+	huge := &TrackingID{
+		Protocol:      tid.Protocol,
+		Digest:        tid.Digest,
+		PartiesState:  bytes.Repeat([]byte{0xff}, DefaultTrackingIDLimits.MaxPartiesStateBytes+1),
+		AuxiliaryData: bytes.Repeat([]byte{0xcd}, DefaultTrackingIDLimits.MaxAuxiliaryDataBytes+1),
+	}
+
+	var hugeParsed TrackingID
+	if err := hugeParsed.FromString(huge.ToString()); err != nil || !hugeParsed.Equals(huge) {
+		t.Fatalf("FromString(ToString()) beyond the default limits: %v", err)
+	}
+
+	if err := hugeParsed.FromStringWithLimits(huge.ToString(), DefaultTrackingIDLimits); !errors.Is(err, errTrackidPartTooLong) {
+		t.Fatalf("expected errTrackidPartTooLong with the default limits, got %v", err)
+	}
+
+	// short parts keep the version 1 format
+	short := &TrackingID{Protocol: 1, Digest: []byte{1}, PartiesState: []byte{1}}
+	if strings.HasPrefix(short.ToString(), "v2-") {
+		t.Fatalf("expected a version 1 string, got %q", short.ToString())
+	}
+
+	if err := parsed.FromString("v2-" + short.ToString()); err != nil || !parsed.Equals(short) {
+		t.Fatalf("short parts should also parse in the v2 format: %v", err)
+	}
+
+	if err := parsed.FromString("v2-1-aa"); !errors.Is(err, errTrackidInvalidFormat) {
+		t.Fatalf("expected errTrackidInvalidFormat, got %v", err)
+	}
+}
@@ -7,27 +7,198 @@
 package common
 
 import (
+	"crypto/ecdh"
 	"errors"
+	"fmt"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // Used externally to update a LocalParty with a valid ParsedMessage
 // set the `to` field to nil if the message is a broadcast.
 // if it was direct communication, set the `to` field to the PartyID of the recipient.
 func ParseWireMessage(wireBytes []byte, from, to *PartyID) (ParsedMessage, error) {
+	return ParseWireMessageWithOptions(wireBytes, from, to, ParseOptions{})
+}
+
+// ParseOptions tightens the checks applied by ParseWireMessageWithOptions.
+type ParseOptions struct {
+	// Allowlist, when set, restricts the content types that may be instantiated.
+	// Content of any other type is rejected before it is unmarshalled.
+	Allowlist *ContentAllowlist
+
+	// Strict requires a registered protocol, a TrackingID valid within TrackingIDLimits,
+	// content and committee flags that pass MessageImpl.ValidateBasic, and the wrapper,
+	// content and TrackingID to agree on the protocol.
+	Strict bool
+
+	// MaxWireBytes bounds the size of the wire message, or of its envelope if signed. Zero means no limit.
+	MaxWireBytes int
+
+	// MaxContentBytes bounds the size of the serialized content. Zero means no limit.
+	MaxContentBytes int
+
+	// TrackingIDLimits bounds the parts of the TrackingID, if any.
+	// Unlike TrackingID.ValidateWithLimits, MaxDigestBytes applies to all digests.
+	TrackingIDLimits TrackingIDLimits
+
+	// RecursionLimit bounds the nesting depth of the wire message and its content.
+	// Zero applies the default limit of the proto package.
+	RecursionLimit int
+
+	// DiscardUnknown drops unknown fields instead of retaining them in the parsed message.
+	DiscardUnknown bool
+
+	// DecryptionKey, when set, opens unicast messages sealed to the local party, see MessageImpl.WireBytes.
+	// Unicast messages that are not sealed are rejected, broadcasts are never sealed;
+	// as in MessageRouting.IsBroadcast, a `to` with an empty ID denotes a broadcast.
+	DecryptionKey *ecdh.PrivateKey
+
+	// Resolver, when set, replaces protoregistry.GlobalTypes when instantiating content,
+	// e.g. to parse content types kept in a private protoregistry.Types.
+	Resolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+}
+
+// DefaultParseOptions are suited to messages from untrusted peers: messages are bounded to 4MiB,
+// TrackingIDs by DefaultTrackingIDLimits, nesting to 100 levels, and unknown fields are discarded.
+var DefaultParseOptions = ParseOptions{
+	MaxWireBytes:     4 << 20,
+	MaxContentBytes:  4 << 20,
+	TrackingIDLimits: DefaultTrackingIDLimits,
+	RecursionLimit:   100,
+	DiscardUnknown:   true,
+}
+
+func (opts ParseOptions) unmarshalOptions() proto.UnmarshalOptions {
+	return proto.UnmarshalOptions{
+		DiscardUnknown: opts.DiscardUnknown,
+		RecursionLimit: opts.RecursionLimit,
+		Resolver:       opts.Resolver,
+	}
+}
+
+// ParseWireMessageWithOptions is like ParseWireMessage, applying the given options.
+// Messages rejected because of the options yield an *Error naming `from` as culprit.
+func ParseWireMessageWithOptions(wireBytes []byte, from, to *PartyID, opts ParseOptions) (ParsedMessage, error) {
+	if err := checkWireSize(wireBytes, from, to, opts); err != nil {
+		return nil, err
+	}
+
+	wire, err := decodeWireMessage(wireBytes, from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWrappedMessage(wire, from, to, opts)
+}
+
+// decodeWireMessage opens the wire bytes if they are expected to be sealed, and unmarshals the MessageWrapper.
+func decodeWireMessage(wireBytes []byte, from, to *PartyID, opts ParseOptions) (*MessageWrapper, error) {
+	var sealed *SealedMessage
+	if opts.DecryptionKey != nil && to.GetID() != "" {
+		var err error
+		if wireBytes, sealed, err = openWireBytes(wireBytes, from, to, opts.DecryptionKey, opts); err != nil {
+			return nil, culpritError(&MessageWrapper{}, err, unknownRound, from, to)
+		}
+	}
+
 	wire := new(MessageWrapper)
-	if err := proto.Unmarshal(wireBytes, wire); err != nil {
+	if err := opts.unmarshalOptions().Unmarshal(wireBytes, wire); err != nil {
 		return nil, err
 	}
 
-	return parseWrappedMessage(wire, from, to)
+	if sealed != nil {
+		if err := sealed.checkTrackingID(wire); err != nil {
+			return nil, culpritError(wire, err, unknownRound, from, to)
+		}
+	}
+
+	return wire, nil
+}
+
+func checkWireSize(wireBytes []byte, from, to *PartyID, opts ParseOptions) error {
+	if opts.MaxWireBytes > 0 && len(wireBytes) > opts.MaxWireBytes {
+		err := fmt.Errorf("%w: %d bytes, at most %d allowed", errParseTooLarge, len(wireBytes), opts.MaxWireBytes)
+		return culpritError(&MessageWrapper{}, err, unknownRound, from, to)
+	}
+
+	return nil
 }
 
-var errParse = errors.New("ParseWireMessage: the message contained unknown content")
+var (
+	errParse          = errors.New("ParseWireMessage: the message contained unknown content")
+	errParseProtocol  = errors.New("ParseWireMessage: the message declared an unknown protocol")
+	errParseNoContent = errors.New("ParseWireMessage: the message has no content")
+	errParseTooLarge  = errors.New("ParseWireMessage: the message is too large")
+
+	errParseContentTooLarge = errors.New("ParseWireMessage: the message content is too large")
+
+	errParseNoTrackingID       = errors.New("ParseWireMessage: the message has no TrackingID")
+	errParseInvalidTrackingID  = errors.New("ParseWireMessage: the message has an invalid TrackingID")
+	errParseInvalidContent     = errors.New("ParseWireMessage: the message content failed ValidateBasic")
+	errParseProtocolMismatch   = errors.New("ParseWireMessage: the message content declares a different protocol")
+	errParseTrackingIDProtocol = errors.New("ParseWireMessage: the message TrackingID declares a different protocol")
+	errParseCommitteeFlags     = errors.New("ParseWireMessage: the message committee flags are not valid for its protocol")
+)
+
+// unknownRound is reported in errors raised before the round of a message is known.
+const unknownRound = -1
 
-func parseWrappedMessage(wire *MessageWrapper, from, to *PartyID) (ParsedMessage, error) {
-	m, err := wire.Message.UnmarshalNew()
+// culpritError blames the sender of a wire message.
+func culpritError(wire *MessageWrapper, err error, round int, from, to *PartyID) *Error {
+	task := "ParseWireMessage"
+	if wire.Protocol != "" {
+		task = wire.Protocol
+	}
+
+	if wire.TrackingID != nil {
+		return NewTrackableError(err, task, round, to, wire.TrackingID, from)
+	}
+
+	return NewError(err, task, round, to, from)
+}
+
+func parseWrappedMessage(wire *MessageWrapper, from, to *PartyID, opts ParseOptions) (ParsedMessage, error) {
+	if !ProtocolType(wire.Protocol).IsRegistered() {
+		if opts.Strict {
+			return nil, culpritError(wire, fmt.Errorf("%w: %q", errParseProtocol, wire.Protocol), unknownRound, from, to)
+		}
+
+		return nil, errParseProtocol
+	}
+
+	if opts.MaxContentBytes > 0 && len(wire.Message.GetValue()) > opts.MaxContentBytes {
+		err := fmt.Errorf("%w: %d bytes, at most %d allowed", errParseContentTooLarge, len(wire.Message.GetValue()), opts.MaxContentBytes)
+		return nil, culpritError(wire, err, unknownRound, from, to)
+	}
+
+	if wire.TrackingID != nil {
+		if err := wire.TrackingID.validateSizes(opts.TrackingIDLimits); err != nil {
+			// the TrackingID is not attached to the error, as it is oversized.
+			return nil, culpritError(&MessageWrapper{Protocol: wire.Protocol}, err, unknownRound, from, to)
+		}
+	}
+
+	allowedRound := unknownRound
+	if opts.Allowlist != nil {
+		if wire.Message == nil {
+			return nil, culpritError(wire, errParseNoContent, unknownRound, from, to)
+		}
+
+		round, err := opts.Allowlist.check(ProtocolType(wire.Protocol), wire.Message.MessageName())
+		if err != nil {
+			return nil, culpritError(wire, fmt.Errorf("%w (type URL %q)", err, wire.Message.GetTypeUrl()), unknownRound, from, to)
+		}
+
+		allowedRound = round
+	}
+
+	m, err := anypb.UnmarshalNew(wire.Message, opts.unmarshalOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -46,5 +217,51 @@ func parseWrappedMessage(wire *MessageWrapper, from, to *PartyID) (ParsedMessage
 		return nil, errParse
 	}
 
+	if opts.Allowlist != nil && content.RoundNumber() != allowedRound {
+		err := fmt.Errorf("%w: %s declares round %d, allowed in round %d", errContentRoundMismatch, proto.MessageName(content), content.RoundNumber(), allowedRound)
+		return nil, culpritError(wire, err, content.RoundNumber(), from, to)
+	}
+
+	if opts.Strict {
+		if err := checkConsistency(wire, content, opts.TrackingIDLimits); err != nil {
+			return nil, culpritError(wire, err, content.RoundNumber(), from, to)
+		}
+	}
+
 	return NewMessage(meta, content, wire), nil
 }
+
+// checkConsistency verifies the TrackingID, committee flags and content of a wire message, and that they agree with the wrapper.
+func checkConsistency(wire *MessageWrapper, content MessageContent, limits TrackingIDLimits) error {
+	if wire.TrackingID == nil {
+		return errParseNoTrackingID
+	}
+
+	if err := wire.TrackingID.ValidateWithLimits(limits); err != nil {
+		return fmt.Errorf("%w: %w", errParseInvalidTrackingID, err)
+	}
+
+	protocol := ProtocolType(wire.Protocol)
+	if content.GetProtocol() != protocol {
+		return fmt.Errorf("%w: wrapper %s, content %s", errParseProtocolMismatch, protocol, content.GetProtocol())
+	}
+
+	trackingProtocol, err := wire.TrackingID.GetProtocolType()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errParseInvalidTrackingID, err)
+	}
+
+	if trackingProtocol != protocol {
+		return fmt.Errorf("%w: wrapper %s, TrackingID %s", errParseTrackingIDProtocol, protocol, trackingProtocol)
+	}
+
+	if !validateCommitteeFlags(wire, protocol) {
+		return errParseCommitteeFlags
+	}
+
+	if !content.ValidateBasic() {
+		return errParseInvalidContent
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	valid := testTrackingID(t, ProtocolECDSASign, 1)
+	invalid := &TrackingID{Protocol: valid.Protocol}
+	otherProtocol := testTrackingID(t, ProtocolFROSTSign, 1)
+
+	content := func(protocol ProtocolType) *testContent {
+		return &testContent{SignatureData: &SignatureData{}, protocol: protocol}
+	}
+
+	tests := []struct {
+		name       string
+		trackingID *TrackingID
+		content    *testContent
+		err        error
+	}{
+		{"consistent", valid, content(ProtocolECDSASign), nil},
+		{"missing TrackingID", nil, content(ProtocolECDSASign), errParseNoTrackingID},
+		{"invalid TrackingID", invalid, content(ProtocolECDSASign), errParseInvalidTrackingID},
+		{"content protocol mismatch", valid, content(ProtocolFROSTSign), errParseProtocolMismatch},
+		{"TrackingID protocol mismatch", otherProtocol, content(ProtocolECDSASign), errParseTrackingIDProtocol},
+		{"invalid content", valid, &testContent{protocol: ProtocolECDSASign}, errParseInvalidContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := &MessageWrapper{Protocol: string(ProtocolECDSASign), TrackingID: tt.trackingID}
+
+			err := checkConsistency(wire, tt.content, DefaultTrackingIDLimits)
+			if tt.err == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("committee flags", func(t *testing.T) {
+		wire := &MessageWrapper{Protocol: string(ProtocolECDSASign), TrackingID: valid, IsToOldCommittee: true}
+		if err := checkConsistency(wire, content(ProtocolECDSASign), DefaultTrackingIDLimits); !errors.Is(err, errParseCommitteeFlags) {
+			t.Fatalf("expected errParseCommitteeFlags, got %v", err)
+		}
+
+		reshare := testTrackingID(t, ProtocolECDSAReshare, 1)
+		wire = &MessageWrapper{Protocol: string(ProtocolECDSAReshare), TrackingID: reshare, IsToOldCommittee: true}
+		if err := checkConsistency(wire, content(ProtocolECDSAReshare), DefaultTrackingIDLimits); err != nil {
+			t.Fatalf("expected resharing to allow the flag, got %v", err)
+		}
+
+		wire.IsToOldAndNewCommittees = true
+		if err := checkConsistency(wire, content(ProtocolECDSAReshare), DefaultTrackingIDLimits); !errors.Is(err, errParseCommitteeFlags) {
+			t.Fatalf("expected errParseCommitteeFlags, got %v", err)
+		}
+	})
+}
+
+func TestCulpritError(t *testing.T) {
+	from, to := NewPartyID("sender", "sender"), NewPartyID("receiver", "receiver")
+	wire := &MessageWrapper{Protocol: string(ProtocolECDSASign)}
+
+	err := culpritError(wire, errParseNoTrackingID, 2, from, to)
+	if !errors.Is(err, errParseNoTrackingID) {
+		t.Fatalf("expected errParseNoTrackingID, got %v", err)
+	}
+
+	if err.Task() != string(ProtocolECDSASign) || err.Round() != 2 || err.Victim() != to {
+		t.Fatalf("unexpected error fields: %v", err)
+	}
+
+	if len(err.Culprits()) != 1 || err.Culprits()[0] != from {
+		t.Fatalf("expected the sender as culprit, got %v", err.Culprits())
+	}
+
+	if err.TrackingId() != nil {
+		t.Fatal("expected no TrackingID")
+	}
+}
+
+func TestParseWireMessageWithOptions_Limits(t *testing.T) {
+	from, to := NewPartyID("sender", "sender"), NewPartyID("receiver", "receiver")
+	trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+
+	t.Run("wire too large", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{Signature: make([]byte, 64)}, trackingID)
+
+		opts := ParseOptions{MaxWireBytes: len(bz) - 1}
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errParseTooLarge) {
+			t.Fatalf("expected errParseTooLarge, got %v", err)
+		}
+
+		opts.MaxWireBytes = len(bz)
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); errors.Is(err, errParseTooLarge) {
+			t.Fatalf("expected the limit to be inclusive, got %v", err)
+		}
+	})
+
+	t.Run("content too large", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{Signature: make([]byte, 64)}, trackingID)
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, ParseOptions{MaxContentBytes: 32})
+		if !errors.Is(err, errParseContentTooLarge) {
+			t.Fatalf("expected errParseContentTooLarge, got %v", err)
+		}
+
+		var perr *Error
+		if !errors.As(err, &perr) || perr.Culprits()[0] != from {
+			t.Fatalf("expected the sender as culprit, got %v", err)
+		}
+	})
+
+	t.Run("TrackingID too large", func(t *testing.T) {
+		large := proto.Clone(trackingID).(*TrackingID)
+		large.AuxiliaryData = make([]byte, 64)
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, large)
+
+		opts := ParseOptions{TrackingIDLimits: TrackingIDLimits{MaxAuxiliaryDataBytes: 32}}
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errTrackidPartTooLong) {
+			t.Fatalf("expected errTrackidPartTooLong, got %v", err)
+		}
+
+		opts = ParseOptions{TrackingIDLimits: TrackingIDLimits{MaxDigestBytes: 16}}
+		if _, err := ParseWireMessageWithOptions(bz, from, to, opts); !errors.Is(err, errTrackidPartTooLong) {
+			t.Fatalf("expected the digest limit to apply to legacy digests, got %v", err)
+		}
+	})
+
+	t.Run("recursion limit", func(t *testing.T) {
+		nested := structpb.NewListValue(&structpb.ListValue{})
+		for i := 0; i < 50; i++ {
+			nested = structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{nested}})
+		}
+
+		bz := wireBytesWith(t, ProtocolECDSASign, nested, nil)
+
+		if _, err := ParseWireMessageWithOptions(bz, from, to, ParseOptions{RecursionLimit: 20}); err == nil || errors.Is(err, errParse) {
+			t.Fatalf("expected the recursion limit to reject the content, got %v", err)
+		}
+
+		// within the default limit, the content is unmarshalled and rejected as unknown.
+		if _, err := ParseWireMessageWithOptions(bz, from, to, ParseOptions{}); !errors.Is(err, errParse) {
+			t.Fatalf("expected errParse, got %v", err)
+		}
+	})
+}
+
+func TestParseOptions_UnmarshalOptions(t *testing.T) {
+	uopts := DefaultParseOptions.unmarshalOptions()
+	if !uopts.DiscardUnknown || uopts.RecursionLimit != DefaultParseOptions.RecursionLimit {
+		t.Fatalf("unexpected unmarshal options: %+v", uopts)
+	}
+
+	// unknown fields are dropped from the wrapper.
+	bz := protowire.AppendTag(wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, nil), 1000, protowire.VarintType)
+	bz = protowire.AppendVarint(bz, 1)
+
+	wire := new(MessageWrapper)
+	if err := uopts.Unmarshal(bz, wire); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wire.ProtoReflect().GetUnknown()) != 0 {
+		t.Fatal("expected unknown fields to be discarded")
+	}
+}
+
+// testContentResolver instantiates SignatureData content as testContent, so that parsing yields MessageContent.
+type testContentResolver struct {
+	*protoregistry.Types
+	protocol ProtocolType
+	round    int
+}
+
+func (r testContentResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := r.Types.FindMessageByURL(url)
+	if err != nil || mt.Descriptor().FullName() != (&SignatureData{}).ProtoReflect().Descriptor().FullName() {
+		return mt, err
+	}
+
+	return testContentType{MessageType: mt, protocol: r.protocol, round: r.round}, nil
+}
+
+type testContentType struct {
+	protoreflect.MessageType
+	protocol ProtocolType
+	round    int
+}
+
+func (mt testContentType) New() protoreflect.Message {
+	content := &testContent{SignatureData: &SignatureData{}, protocol: mt.protocol, round: mt.round}
+	return testContentReflect{Message: content.SignatureData.ProtoReflect(), content: content}
+}
+
+type testContentReflect struct {
+	protoreflect.Message
+	content *testContent
+}
+
+func (m testContentReflect) Interface() protoreflect.ProtoMessage { return m.content }
+
+func TestParseWireMessage_RegisteredProtocol(t *testing.T) {
+	from, to := NewPartyID("sender", "sender"), NewPartyID("receiver", "receiver")
+	custom := ProtocolInfo{Name: "TEST:WIRE", ID: 94}
+	bz := wireBytesWith(t, custom.Name, &SignatureData{}, nil)
+
+	if _, err := ParseWireMessage(bz, from, to); err != errParseProtocol {
+		t.Fatalf("expected errParseProtocol before registration, got %v", err)
+	}
+
+	if err := RegisterProtocol(custom); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterProtocol(custom.Name) })
+
+	opts := ParseOptions{Resolver: testContentResolver{Types: protoregistry.GlobalTypes, protocol: custom.Name}}
+	msg, err := ParseWireMessageWithOptions(bz, from, to, opts)
+	if err != nil {
+		t.Fatalf("expected a protocol registered at runtime to be accepted, got %v", err)
+	}
+
+	if msg.GetProtocol() != custom.Name {
+		t.Fatalf("GetProtocol() = %s, want %s", msg.GetProtocol(), custom.Name)
+	}
+}
+
+func TestParseWireMessageWithOptions_Strict(t *testing.T) {
+	from, to := NewPartyID("sender", "sender"), NewPartyID("receiver", "receiver")
+	trackingID := testTrackingID(t, ProtocolECDSASign, 1)
+
+	opts := ParseOptions{
+		Strict:   true,
+		Resolver: testContentResolver{Types: protoregistry.GlobalTypes, protocol: ProtocolECDSASign, round: 1},
+	}
+
+	assertCulprit := func(t *testing.T, err, expected error) {
+		t.Helper()
+
+		if !errors.Is(err, expected) {
+			t.Fatalf("expected %v, got %v", expected, err)
+		}
+
+		var perr *Error
+		if !errors.As(err, &perr) || len(perr.Culprits()) != 1 || perr.Culprits()[0] != from {
+			t.Fatalf("expected the sender as culprit, got %v", err)
+		}
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{Signature: []byte{1}}, trackingID)
+
+		msg, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if msg.GetProtocol() != ProtocolECDSASign || msg.Content().RoundNumber() != 1 || !msg.WireMsg().TrackingID.Equals(trackingID) {
+			t.Fatalf("unexpected parsed message %v", msg)
+		}
+	})
+
+	t.Run("missing TrackingID", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, nil)
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		assertCulprit(t, err, errParseNoTrackingID)
+	})
+
+	t.Run("TrackingID protocol mismatch", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, testTrackingID(t, ProtocolFROSTSign, 1))
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		assertCulprit(t, err, errParseTrackingIDProtocol)
+	})
+
+	t.Run("content protocol mismatch", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolFROSTSign, &SignatureData{}, testTrackingID(t, ProtocolFROSTSign, 1))
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		assertCulprit(t, err, errParseProtocolMismatch)
+	})
+
+	t.Run("unknown protocol", func(t *testing.T) {
+		bz := wireBytesWith(t, ProtocolType("UNKNOWN"), &SignatureData{}, trackingID)
+
+		_, err := ParseWireMessageWithOptions(bz, from, to, opts)
+		assertCulprit(t, err, errParseProtocol)
+
+		if _, err := ParseWireMessage(bz, from, to); err != errParseProtocol {
+			t.Fatalf("expected a bare errParseProtocol outside strict mode, got %v", err)
+		}
+	})
+
+	t.Run("raised TrackingID limits", func(t *testing.T) {
+		large := proto.Clone(trackingID).(*TrackingID)
+		large.AuxiliaryData = bytes.Repeat([]byte{1}, 2*DefaultTrackingIDLimits.MaxAuxiliaryDataBytes)
+		bz := wireBytesWith(t, ProtocolECDSASign, &SignatureData{}, large)
+
+		raised := opts
+		raised.TrackingIDLimits = TrackingIDLimits{MaxAuxiliaryDataBytes: len(large.AuxiliaryData)}
+		if _, err := ParseWireMessageWithOptions(bz, from, to, raised); err != nil {
+			t.Fatalf("strict mode must apply the configured limits: %v", err)
+		}
+
+		raised.TrackingIDLimits = DefaultTrackingIDLimits
+		if _, err := ParseWireMessageWithOptions(bz, from, to, raised); !errors.Is(err, errTrackidPartTooLong) {
+			t.Fatalf("expected errTrackidPartTooLong, got %v", err)
+		}
+	})
+}